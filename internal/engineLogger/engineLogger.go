@@ -0,0 +1,30 @@
+// Package engineLogger holds the optional slog logger of one engine package, so the packages share how a logger is
+// set and how they stay silent without one.
+package engineLogger
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Logger is the logger of one package. The zero value discards everything until Set is called.
+type Logger struct {
+	logger atomic.Pointer[slog.Logger]
+}
+
+// discardLogger is returned while no logger is set.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// Set replaces the logger. Nil discards everything again.
+func (packageLogger *Logger) Set(logger *slog.Logger) {
+	packageLogger.logger.Store(logger)
+}
+
+// Get returns the logger that was set, or a logger that discards everything.
+func (packageLogger *Logger) Get() *slog.Logger {
+	if logger := packageLogger.logger.Load(); logger != nil {
+		return logger
+	}
+
+	return discardLogger
+}
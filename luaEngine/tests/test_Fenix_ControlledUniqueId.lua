@@ -5,7 +5,7 @@ local tests = {}
 
 -- Mock input table structure
 local function createInputTable(text, seed)
-    return {"Fenix_ControlledUniqueId", {0}, {text}, {seed}}
+    return {"Fenix_ControlledUniqueId", {0}, {text}, {true, seed}}
 end
 
 -- Test for date in YYYY-MM-DD format
@@ -16,7 +16,7 @@ function tests.test_dateFormatYYYYMMDD()
 
     local expectedDate = os.date("%Y-%m-%d")
     local expectedString = "Today's date is " .. expectedDate
-    assert.is_equal(result, expectedString)
+    assert.is_equal(expectedString, result.value)
 end
 
 -- Test for time in hh:mm:ss format
@@ -27,7 +27,7 @@ function tests.test_timeFormathhmmss()
 
     local expectedTime = os.date("%H:%M:%S")
     local expectedString = "Current time is " .. expectedTime
-    assert.is_equal(result, expectedString)
+    assert.is_equal(expectedString, result.value)
 end
 
 -- Test for random number of length n
@@ -36,26 +36,26 @@ function tests.test_randomNumberLength()
     local inputTable = createInputTable(inputString, 12345) -- Fixed seed for predictability
     local result = Fenix_ControlledUniqueId(inputTable)
 
-    assert.is_equal(#result:match("%d+"), 5)  -- Check if the length of the number is 5
+    assert.is_equal(#result.value:match("%d+"), 5)  -- Check if the length of the number is 5
 end
 
 function tests.test_randomSmallLetterLength()
-    local inputString = "Random small letters: %a(5, 12345)%"
+    local inputString = "Random small letters: %a(5; 12345)%"
     local inputTable = createInputTable(inputString, 12345)  -- Fixed seed
     local result = Fenix_ControlledUniqueId(inputTable)
 
-    local match = result:match("Random small letters: (%a+)")
+    local match = result.value:match("Random small letters: (%a+)")
     assert.is_equal(#match, 5)  -- Check if the length of the string is 5
     assert.matches("^[a-z]+$", match)  -- Check if all characters are small letters
 end
 
 
 function tests.test_randomCapitalLetterLength()
-    local inputString = "Random capital letters: %A(5, 12345)%"
+    local inputString = "Random capital letters: %A(5; 12345)%"
     local inputTable = createInputTable(inputString, 12345)  -- Fixed seed
     local result = Fenix_ControlledUniqueId(inputTable)
 
-    local match = result:match("Random capital letters: (%a+)")
+    local match = result.value:match("Random capital letters: (%a+)")
     assert.is_equal(#match, 5)  -- Check if the length of the string is 5
     assert.matches("^[A-Z]+$", match)  -- Check if all characters are capital letters
 end
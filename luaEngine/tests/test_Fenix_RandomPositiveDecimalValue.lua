@@ -4,13 +4,16 @@ local mymodule = require('src/Fenix_RandomPositiveDecimalValue')
 
 local tests = {}
 
+-- The expected values are the values the Go handler gives for the same parameters and entropy,
+-- see TestGoFenixRandomPositiveDecimalValue_ShouldGiveLuaUnitTestExpectations in scriptEngine.
+
 -- OK - {"Fenix_RandomPositiveDecimalValue", {},{2, 3}, {0}}
 function tests.ok_array__parameters_2_3_entropi_0()
 
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {},{2, 3}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "84.394"
+    local expectedDateResponse = "60.940"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -24,7 +27,7 @@ function tests.ok_array_1_parameters_2_3_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {1},{2, 3}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "84.394"
+    local expectedDateResponse = "60.940"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -38,7 +41,7 @@ function tests.ok_array__parameters_1_2_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {},{1, 2}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "8.39"
+    local expectedDateResponse = "6.94"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -52,7 +55,7 @@ function tests.ok_array_2_parameters_1_2_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {2},{1, 2}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "7.80"
+    local expectedDateResponse = "1.26"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -67,7 +70,7 @@ function tests.ok_array__parameters_1_1_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {},{1, 1}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "8.3"
+    local expectedDateResponse = "6.9"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -81,7 +84,7 @@ function tests.ok_array__parameters_1_1_entropi_1()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {},{1, 1}, {true, 1}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "7.8"
+    local expectedDateResponse = "1.2"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -95,7 +98,7 @@ function tests.ok_array_1_parameters_1_0_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {1},{1, 0}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "8"
+    local expectedDateResponse = "6"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -137,7 +140,7 @@ function tests.ok_array__parameters_6_6_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {0},{6, 6}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "840187.394382"
+    local expectedDateResponse = "945196.244965"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -151,7 +154,7 @@ function tests.ok_array__parameters_6_10_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {0},{6, 10}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "840187.3943829300"
+    local expectedDateResponse = "945196.2449650852"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -179,7 +182,7 @@ function tests.ok_array__parameters_0_2_3_4_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {0},{0, 2, 3, 4}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "000.3900"
+    local expectedDateResponse = "000.2400"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -193,7 +196,7 @@ function tests.ok_array__parameters_2_2_3_4_entropi_0()
 
     local inputArray =  {"Fenix_RandomPositiveDecimalValue", {0},{2, 2, 3, 4}, {true, 0}}
     local response = Fenix_RandomPositiveDecimalValue(inputArray)
-    local expectedDateResponse = "084.3900"
+    local expectedDateResponse = "094.2400"
 
     assert.is_equal(expectedDateResponse, response.value)
     assert.is_equal(true, response.success)
@@ -9,19 +9,47 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// RenderOptions holds optional settings for one rendering of a template.
+type RenderOptions struct {
+	// Entropy scope IDs for this rendering, for example the template instance ID used by '(scope=template)'.
+	// Non-empty IDs replace the IDs of the started execution.
+	EntropyScopeIDs scriptEngine.EntropyScopeIDs
+	// Number of placeholder functions evaluated at the same time. 0 or 1 evaluates them one by one in template order.
+	// Lua functions run in parallel up to the Lua state pool size, see scriptEngine.LuaEngineOptions.PoolSize.
+	MaxParallelPlaceholders int
+}
+
+// placeholderEvaluation is one placeholder function whose value is written into its segment after evaluation.
+type placeholderEvaluation struct {
+	functionValueSlice []interface{}
+	segmentWithValue   *widget.TextSegment
+}
+
 func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[string]string, randomUuidForScriptEngine string) (
 	tempRichText *widget.RichText,
 	tempRichTextWithValues *widget.RichText,
 	tempPureText string) {
 
+	return ParseAndFormatPlaceholdersWithOptions(inputText, testDataPointValuesPtr, randomUuidForScriptEngine, RenderOptions{})
+}
+
+// ParseAndFormatPlaceholdersWithOptions works as ParseAndFormatPlaceholders, with settings for this rendering.
+func ParseAndFormatPlaceholdersWithOptions(inputText string, testDataPointValuesPtr *map[string]string, randomUuidForScriptEngine string, renderOptions RenderOptions) (
+	tempRichText *widget.RichText,
+	tempRichTextWithValues *widget.RichText,
+	tempPureText string) {
+
 	var testDataPointValues map[string]string
 	testDataPointValues = *testDataPointValuesPtr
 
 	var segments []widget.RichTextSegment
 	var segmentsWithValues []widget.RichTextSegment
 
+	var placeholderEvaluations []placeholderEvaluation
+
 	var currentText string
 	var inputText_secondpart string
 	var inputText_lenght, inputText_secondpart_lenght int
@@ -58,6 +86,7 @@ func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[st
 			currentText = inputText[startIndex : endIndex+2] // +2 to include the closing braces
 
 			var newTextFromScriptEngine string
+			var functionValueSlice []interface{}
 			testDataToReplace := strings.TrimSpace(currentText[2 : len(currentText)-2]) // remove '{{' and '}}'
 			testDataColumnDataName, isTestDataReference, isMalformedTestDataReference := extractTestDataColumnDataName(testDataToReplace)
 
@@ -74,13 +103,10 @@ func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[st
 				newTextFromScriptEngine = currentText + " - is not a correct TestData-reference"
 
 			} else {
-				functionValueSlice, err := match(currentText)
-				if err == nil {
-					//newTextFromScriptEngine = tengoScriptExecuter.ExecuteScripte(functionValueSlice)
-					newTextFromScriptEngine = scriptEngine.ExecuteLuaScriptBasedOnPlaceholder(
-						functionValueSlice, randomUuidForScriptEngine)
-
-				} else {
+				var err error
+				functionValueSlice, err = match(currentText)
+				if err != nil {
+					functionValueSlice = nil
 					newTextFromScriptEngine = err.Error()
 				}
 			}
@@ -92,13 +118,22 @@ func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[st
 				},
 			})
 
-			segmentsWithValues = append(segmentsWithValues, &widget.TextSegment{
+			segmentWithValue := &widget.TextSegment{
 				Text: newTextFromScriptEngine,
 				Style: widget.RichTextStyle{
 					Inline:    true,
 					TextStyle: fyne.TextStyle{Bold: true},
 				},
-			})
+			}
+			segmentsWithValues = append(segmentsWithValues, segmentWithValue)
+
+			// The placeholder function is evaluated when the whole template has been parsed
+			if functionValueSlice != nil {
+				placeholderEvaluations = append(placeholderEvaluations, placeholderEvaluation{
+					functionValueSlice: functionValueSlice,
+					segmentWithValue:   segmentWithValue,
+				})
+			}
 
 			// Move past this segment
 			inputText = inputText[endIndex+2:]
@@ -110,6 +145,8 @@ func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[st
 		}
 	}
 
+	evaluatePlaceholders(placeholderEvaluations, testDataPointValues, randomUuidForScriptEngine, renderOptions)
+
 	// Create Response for RichText without value
 	tempRichText = &widget.RichText{
 		BaseWidget: widget.BaseWidget{},
@@ -137,6 +174,63 @@ func ParseAndFormatPlaceholders(inputText string, testDataPointValuesPtr *map[st
 	return tempRichText, tempRichTextWithValues, tempPureText
 }
 
+// evaluatePlaceholders executes the placeholder functions of one rendering with at most
+// 'MaxParallelPlaceholders' at the same time. Each value is written into its own segment,
+// so the output order does not depend on which evaluation finishes first.
+func evaluatePlaceholders(placeholderEvaluations []placeholderEvaluation, testDataPointValues map[string]string, randomUuidForScriptEngine string, renderOptions RenderOptions) {
+	numberOfWorkers := renderOptions.MaxParallelPlaceholders
+	if numberOfWorkers > len(placeholderEvaluations) {
+		numberOfWorkers = len(placeholderEvaluations)
+	}
+
+	if numberOfWorkers <= 1 {
+		for _, evaluation := range placeholderEvaluations {
+			evaluation.segmentWithValue.Text = executePlaceholder(
+				evaluation.functionValueSlice, testDataPointValues, randomUuidForScriptEngine, renderOptions)
+		}
+		return
+	}
+
+	evaluationIndexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < numberOfWorkers; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for evaluationIndex := range evaluationIndexes {
+				evaluation := placeholderEvaluations[evaluationIndex]
+				evaluation.segmentWithValue.Text = executePlaceholder(
+					evaluation.functionValueSlice, testDataPointValues, randomUuidForScriptEngine, renderOptions)
+			}
+		}()
+	}
+
+	for evaluationIndex := range placeholderEvaluations {
+		evaluationIndexes <- evaluationIndex
+	}
+	close(evaluationIndexes)
+	waitGroup.Wait()
+}
+
+// executePlaceholder executes one parsed placeholder with the render options applied.
+// The TestData values of the rendering are passed on, so Lua functions can look them up with 'fenix.testdata'.
+// Errors are returned as text, in the same way as scriptEngine.ExecuteLuaScriptBasedOnPlaceholder.
+func executePlaceholder(functionValueSlice []interface{}, testDataPointValues map[string]string, randomUuidForScriptEngine string, renderOptions RenderOptions) string {
+	request, err := scriptEngine.ParsePlaceholderExecutionRequest(functionValueSlice, randomUuidForScriptEngine)
+	if err != nil {
+		return err.Error()
+	}
+	request.EntropyScopeIDs = renderOptions.EntropyScopeIDs
+	request.TestDataValues = testDataPointValues
+
+	value, err := scriptEngine.ExecutePlaceholder(request)
+	if err != nil {
+		return err.Error()
+	}
+
+	return value
+}
+
 // extractTestDataColumnDataName parses TestData placeholders.
 // Preferred format is `TestData.<context>.<columnName>`.
 // Legacy format `<context>.TestData.<columnName>` is still accepted.
@@ -192,7 +286,7 @@ func match(text string) (mainScriptInputSlice []interface{}, err error) {
 	var functionArgumentSlice []interface{}
 
 	//regExPattern := `\{\{([a-zA-Z0-9_.]+)(?:\[(\d*(?:,\s*\d*)*)\])?\((.*?)\)\}(?:\((true|false)(?:,\s*(\d+))?\))?\}`
-	regExPattern := `\{\{([a-zA-Z0-9_.]+)(?:\[([-+]?\d*(?:,\s*[-+]?\d*)*)\])?\((.*?)\)\}(?:\((true|false)(?:,\s*(\d+))?\))?\}`
+	regExPattern := `\{\{([a-zA-Z0-9_.]+)(?:\[([-+]?\d*(?:,\s*[-+]?\d*)*)\])?\((.*?)\)((?:\.[a-zA-Z0-9_]+)*)\}(?:\((?:(true|false)(?:,\s*(\d+))?(?:,\s*scope=([a-zA-Z]+))?|scope=([a-zA-Z]+))\))?\}`
 	/*
 		Explanation of Each Part
 		\{\{: Matches literal {{.
@@ -201,8 +295,10 @@ func match(text string) (mainScriptInputSlice []interface{}, err error) {
 		[-+]?\d*: Matches an optional sign (+ or -), followed by any digits.
 		(?:,\s*[-+]?\d*)*: Matches zero or more repetitions of a comma, optional spaces, an optional sign, and digits, allowing for lists of indices.
 		\((.*?)\): Captures the arguments within parentheses. .*? is used for lazy matching to stop at the first ).
+		((?:\.[a-zA-Z0-9_]+)*): Captures an optional field path into a structured result, e.g. .zip or .lines.1
 		\}: Matches literal }.
-		(?:\((true|false)(?:,\s*(\d+))?\))?: Optional non-capturing group for additional parameters like boolean values and numbers, typically used for configurations or flags.
+		(?:\((?:(true|false)(?:,\s*(\d+))?(?:,\s*scope=([a-zA-Z]+))?|scope=([a-zA-Z]+))\))?: Optional non-capturing group for the entropy tail,
+			either boolean and extra entropy with an optional trailing entropy scope, or only an entropy scope, e.g. (true, 5, scope=step) or (scope=step).
 	*/
 	re := regexp.MustCompile(regExPattern)
 
@@ -213,8 +309,10 @@ func match(text string) (mainScriptInputSlice []interface{}, err error) {
 		functionName := matches[1]
 		arrayIndexes := matches[2]
 		functionArgs := matches[3]
-		useEntropyFromTestCaseExecutionUuid := matches[4]
-		addExtraEntropyValue := matches[5]
+		fieldPath := matches[4]
+		useEntropyFromTestCaseExecutionUuid := matches[5]
+		addExtraEntropyValue := matches[6]
+		entropyScopeName := matches[7] + matches[8]
 
 		// Add 'placeholder' to 'mainScriptInputSlice'
 		mainScriptInputSlice = append(mainScriptInputSlice, placeholder)
@@ -305,8 +403,32 @@ func match(text string) (mainScriptInputSlice []interface{}, err error) {
 			mainScriptInputSlice = append(mainScriptInputSlice, tempExtraEntropy)
 		}
 
+		// The entropy scope and field path are only added when used, so plain placeholders keep the 6 element format.
+		var entropyScope scriptEngine.EntropyScope
+		if len(entropyScopeName) > 0 {
+
+			entropyScope, err = scriptEngine.ParseEntropyScope(entropyScopeName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(entropyScopeName) > 0 || len(fieldPath) > 0 {
+			mainScriptInputSlice = append(mainScriptInputSlice, string(entropyScope))
+		}
+
+		if len(fieldPath) > 0 {
+
+			var fieldPathSlice []interface{}
+			for _, field := range strings.Split(strings.TrimPrefix(fieldPath, "."), ".") {
+				fieldPathSlice = append(fieldPathSlice, field)
+			}
+
+			mainScriptInputSlice = append(mainScriptInputSlice, fieldPathSlice)
+		}
+
 	} else {
-		fmt.Println("No match found for:", text)
+		placeholderReplacementEngineLogger().Debug("no placeholder match found", "text", text)
 		err = errors.New(fmt.Sprintf("No match found for '%s'", text))
 	}
 
@@ -0,0 +1,21 @@
+package placeholderReplacementEngine
+
+import (
+	"log/slog"
+
+	"github.com/jlambert68/FenixScriptEngine/internal/engineLogger"
+)
+
+// renderingLog holds the logger for rendering diagnostics.
+var renderingLog engineLogger.Logger
+
+// SetLogger sets where rendering diagnostics go, for example placeholders that don't match the placeholder syntax
+// and are left in the text. Rendering logs nothing by default; pass nil to turn it off again.
+func SetLogger(logger *slog.Logger) {
+	renderingLog.Set(logger)
+}
+
+// placeholderReplacementEngineLogger returns the rendering logger.
+func placeholderReplacementEngineLogger() *slog.Logger {
+	return renderingLog.Get()
+}
@@ -1,8 +1,16 @@
 package placeholderReplacementEngine
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/jlambert68/FenixScriptEngine/scriptEngine"
 )
 
 func logParseAndFormatInput(t *testing.T, callLabel string, template string, testDataMap map[string]string, executionUUID string) {
@@ -82,3 +90,200 @@ func TestParseAndFormatPlaceholders_ShouldStillResolveLegacyTestDataOrder(t *tes
 		t.Fatalf("expected resolved legacy TestData value, got: %s", pureText)
 	}
 }
+
+func TestMatch_ShouldParseEntropyScopeInTail(t *testing.T) {
+	testCases := []struct {
+		name          string
+		placeholder   string
+		expectedSlice int
+		expectedScope interface{}
+	}{
+		{name: "no tail", placeholder: "{{Fenix.TodayShiftDay(0)}}", expectedSlice: 6},
+		{name: "only scope", placeholder: "{{Fenix.TodayShiftDay(0)}(scope=step)}", expectedSlice: 7, expectedScope: "step"},
+		{name: "entropy and scope", placeholder: "{{Fenix.TodayShiftDay(0)}(true, 5, scope=template)}", expectedSlice: 7, expectedScope: "template"},
+		{name: "bool and scope", placeholder: "{{Fenix.TodayShiftDay(0)}(false, scope=Suite)}", expectedSlice: 7, expectedScope: "suite"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			functionValueSlice, err := match(testCase.placeholder)
+			t.Logf("Output [%s]\n  Slice: %v\n  Error: %v", testCase.name, functionValueSlice, err)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if len(functionValueSlice) != testCase.expectedSlice {
+				t.Fatalf("expected %d elements, got %d", testCase.expectedSlice, len(functionValueSlice))
+			}
+			if testCase.expectedSlice == 7 && functionValueSlice[6] != testCase.expectedScope {
+				t.Fatalf("expected scope %v, got %v", testCase.expectedScope, functionValueSlice[6])
+			}
+		})
+	}
+
+	if _, err := match("{{Fenix.TodayShiftDay(0)}(scope=galaxy)}"); err == nil {
+		t.Fatalf("expected error for unknown entropy scope")
+	}
+}
+
+func TestMatch_ShouldLogPlaceholdersWithoutMatchToTheLogger(t *testing.T) {
+	var logBuffer bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	_, err := match("{{not a placeholder")
+	t.Logf("Output [no-match]\n  Error: %v\n  Log: %s", err, logBuffer.String())
+	if err == nil || strings.Contains(logBuffer.String(), `level=DEBUG msg="no placeholder match found" text="{{not a placeholder"`) == false {
+		t.Fatalf("expected no match error and debug record, got: %v", err)
+	}
+}
+
+func TestParseAndFormatPlaceholdersWithOptions_ShouldUseTemplateInstanceScope(t *testing.T) {
+	testDataMap := map[string]string{}
+	template := "{{Fenix.RandomPositiveDecimalValue(6, 2, 6, 2, .)}(true, 0, scope=template)}}"
+	executionUUID := "execution-uuid"
+
+	var pureTexts []string
+	for _, templateInstanceID := range []string{"instance-1", "instance-2", "instance-1"} {
+		logParseAndFormatInput(t, templateInstanceID, template, testDataMap, executionUUID)
+		_, _, pureText := ParseAndFormatPlaceholdersWithOptions(
+			template,
+			&testDataMap,
+			executionUUID,
+			RenderOptions{EntropyScopeIDs: scriptEngine.EntropyScopeIDs{TemplateInstanceID: templateInstanceID}},
+		)
+		logParseAndFormatOutput(t, templateInstanceID, pureText)
+		pureTexts = append(pureTexts, pureText)
+	}
+
+	if pureTexts[0] == pureTexts[1] || pureTexts[0] != pureTexts[2] {
+		t.Fatalf("expected distinct values per template instance and reproducible values per instance, got %v", pureTexts)
+	}
+
+	_, _, pureText := ParseAndFormatPlaceholders(template, &testDataMap, executionUUID)
+	logParseAndFormatOutput(t, "missing-template-instance", pureText)
+	if strings.Contains(pureText, "no template ID is set") == false {
+		t.Fatalf("expected missing template instance error, got: %s", pureText)
+	}
+}
+
+func TestParseAndFormatPlaceholders_ShouldRenderFieldOfStructuredValue(t *testing.T) {
+	if err := scriptEngine.RegisterGoPlaceholderValueFunction("Test_RenderAddress", func(input scriptEngine.GoPlaceholderInput) (scriptEngine.PlaceholderValue, error) {
+		return scriptEngine.MapValue(map[string]scriptEngine.PlaceholderValue{
+			"zip":  scriptEngine.NumberValue(12345),
+			"city": scriptEngine.StringValue("Stockholm"),
+		}), nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+
+	functionValueSlice, err := match("{{Test.RenderAddress().zip}(true, 5)}")
+	t.Logf("Output [match-field-path]\n  Slice: %v\n  Error: %v", functionValueSlice, err)
+	if err != nil || len(functionValueSlice) != 8 || functionValueSlice[6] != "" {
+		t.Fatalf("expected 8 elements with empty scope, got %v (%v)", functionValueSlice, err)
+	}
+
+	testDataMap := map[string]string{}
+	template := "{{Test.RenderAddress().zip}} {{Test.RenderAddress().city}} {{Test.RenderAddress().street}}"
+	executionUUID := "execution-uuid"
+
+	logParseAndFormatInput(t, "field-path", template, testDataMap, executionUUID)
+	_, _, pureText := ParseAndFormatPlaceholders(template, &testDataMap, executionUUID)
+	logParseAndFormatOutput(t, "field-path", pureText)
+
+	if strings.HasPrefix(pureText, "12345 Stockholm ") == false || strings.Contains(pureText, "field 'street' does not exist") == false {
+		t.Fatalf("expected rendered fields and a field error for 'street', got: %s", pureText)
+	}
+}
+
+// parallelTestLuaScript echoes the first argument from Lua.
+const parallelTestLuaScript = `
+function Test_ParallelLuaEcho(inputTable)
+    local functionArgs = inputTable[3]
+    return { success = true, value = "lua-" .. functionArgs[1], errorMessage = "" }
+end
+`
+
+func TestParseAndFormatPlaceholdersWithOptions_ShouldEvaluateInParallelAndKeepOrder(t *testing.T) {
+	var inFlight, maxInFlight int32
+	if err := scriptEngine.RegisterGoPlaceholderFunction("Test_ParallelGoEcho", func(input scriptEngine.GoPlaceholderInput) (string, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) == true {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return "go-" + input.Arguments[0], nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	if err := scriptEngine.InitiateLuaScriptEngine([]scriptEngine.LuaScriptsStruct{
+		{LuaScriptName: "parallelTest", LuaScript: []byte(parallelTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer scriptEngine.CloseDownLuaScriptEngine()
+
+	var templateBuilder strings.Builder
+	for placeholderNumber := 0; placeholderNumber < 16; placeholderNumber++ {
+		templateBuilder.WriteString(fmt.Sprintf("[{{Test.ParallelGoEcho(%d)}} {{Test.ParallelLuaEcho(%d)}} ", placeholderNumber, placeholderNumber))
+		templateBuilder.WriteString("{{Fenix.RandomPositiveDecimalValue(6, 2, 6, 2, .)}(true, " + strconv.Itoa(placeholderNumber) + ")}}]")
+	}
+	template := templateBuilder.String()
+	testDataMap := map[string]string{}
+	executionUUID := "execution-uuid"
+
+	logParseAndFormatInput(t, "sequential", template, testDataMap, executionUUID)
+	_, _, sequentialText := ParseAndFormatPlaceholders(template, &testDataMap, executionUUID)
+	logParseAndFormatOutput(t, "sequential", sequentialText)
+	if maxInFlight != 1 {
+		t.Fatalf("expected sequential evaluation, got %d placeholders at the same time", maxInFlight)
+	}
+
+	atomic.StoreInt32(&maxInFlight, 0)
+	_, _, parallelText := ParseAndFormatPlaceholdersWithOptions(template, &testDataMap, executionUUID, RenderOptions{MaxParallelPlaceholders: 4})
+	logParseAndFormatOutput(t, "parallel", parallelText)
+
+	if parallelText != sequentialText {
+		t.Fatalf("expected parallel rendering to equal sequential rendering\n  sequential: %s\n  parallel:   %s", sequentialText, parallelText)
+	}
+	if strings.HasPrefix(parallelText, "[go-0 lua-0 ") == false || strings.Contains(parallelText, "[go-15 lua-15 ") == false {
+		t.Fatalf("expected values in template order, got: %s", parallelText)
+	}
+	if maxInFlight < 2 || maxInFlight > 4 {
+		t.Fatalf("expected between 2 and 4 placeholders at the same time, got %d", maxInFlight)
+	}
+}
+
+// testDataLuaScript reads a TestData value of the rendering with the 'fenix' module.
+const testDataLuaScript = `
+local fenix = require("fenix")
+
+function Test_TestDataGreeting(inputTable)
+    return { success = true, value = "Hello " .. (fenix.testdata("FirstName") or "nobody"), errorMessage = "" }
+end
+`
+
+func TestParseAndFormatPlaceholders_ShouldPassTestDataToLuaFunctions(t *testing.T) {
+	if err := scriptEngine.InitiateLuaScriptEngine([]scriptEngine.LuaScriptsStruct{
+		{LuaScriptName: "testDataTest", LuaScript: []byte(testDataLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer scriptEngine.CloseDownLuaScriptEngine()
+
+	testDataMap := map[string]string{
+		"FirstName": "Alice",
+	}
+	template := "{{Test.TestDataGreeting()}} / {{TestData.Customer.FirstName}}"
+	executionUUID := "execution-uuid"
+
+	logParseAndFormatInput(t, "testdata-to-lua", template, testDataMap, executionUUID)
+	_, _, pureText := ParseAndFormatPlaceholders(template, &testDataMap, executionUUID)
+	logParseAndFormatOutput(t, "testdata-to-lua", pureText)
+
+	if pureText != "Hello Alice / Alice" {
+		t.Fatalf("expected TestData value in Lua function, got: %s", pureText)
+	}
+}
@@ -0,0 +1,252 @@
+package scriptEngine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PlaceholderAuditRecord describes one resolved placeholder.
+type PlaceholderAuditRecord struct {
+	// Increasing number over all records written by this process.
+	Sequence                    uint64             `json:"sequence"`
+	TestCaseExecutionUUID       string             `json:"testCaseExecutionUuid"`
+	Placeholder                 string             `json:"placeholder"`
+	FunctionName                string             `json:"functionName"`
+	ArrayIndexes                []int              `json:"arrayIndexes"`
+	Arguments                   []string           `json:"arguments"`
+	UseEntropyFromExecutionUUID bool               `json:"useEntropyFromExecutionUuid"`
+	ExtraEntropy                uint64             `json:"extraEntropy"`
+	Entropy                     uint64             `json:"entropy"`
+	EntropyScheme               EntropyScheme      `json:"entropyScheme,omitempty"`
+	EntropyScope                EntropyScope       `json:"entropyScope,omitempty"`
+	EntropyScopeIDs             EntropyScopeIDs    `json:"entropyScopeIds"`
+	ClockTime                   time.Time          `json:"clockTime"`
+	TestDataDomainName          string             `json:"testDataDomainName,omitempty"`
+	TestDataValues              map[string]string  `json:"testDataValues,omitempty"`
+	FieldPath                   []string           `json:"fieldPath,omitempty"`
+	Runtime                     PlaceholderRuntime `json:"runtime"`
+	// Formatted value and the kind of the typed value it was formatted from.
+	Output     string               `json:"output"`
+	OutputKind PlaceholderValueKind `json:"outputKind,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	ErrorKind  PlaceholderErrorKind `json:"errorKind,omitempty"`
+	// Lines printed or logged by Lua during the call.
+	LuaOutput []LuaOutputLine `json:"luaOutput,omitempty"`
+}
+
+// PlaceholderAuditSink receives one record per resolved placeholder.
+type PlaceholderAuditSink interface {
+	WritePlaceholderAuditRecord(record PlaceholderAuditRecord) error
+}
+
+// PlaceholderAuditQuerier is implemented by sinks that can return records for one execution.
+type PlaceholderAuditQuerier interface {
+	PlaceholderAuditRecordsForExecution(testCaseExecutionUUID string) ([]PlaceholderAuditRecord, error)
+}
+
+var (
+	placeholderAuditSinkMutex sync.RWMutex
+	// Active audit sink, nil when auditing is disabled.
+	placeholderAuditSink PlaceholderAuditSink
+	// Sequence counter shared by all audit records.
+	placeholderAuditSequence uint64
+)
+
+// SetPlaceholderAuditSink sets the sink that receives audit records. Use nil to disable auditing.
+func SetPlaceholderAuditSink(sink PlaceholderAuditSink) {
+	placeholderAuditSinkMutex.Lock()
+	placeholderAuditSink = sink
+	placeholderAuditSinkMutex.Unlock()
+}
+
+// GetPlaceholderAuditRecords returns all audit records for one execution from the active sink.
+func GetPlaceholderAuditRecords(testCaseExecutionUUID string) ([]PlaceholderAuditRecord, error) {
+	placeholderAuditSinkMutex.RLock()
+	sink := placeholderAuditSink
+	placeholderAuditSinkMutex.RUnlock()
+
+	if sink == nil {
+		return nil, fmt.Errorf("no placeholder audit sink is configured")
+	}
+
+	querier, ok := sink.(PlaceholderAuditQuerier)
+	if ok == false {
+		return nil, fmt.Errorf("placeholder audit sink %T can not be queried", sink)
+	}
+
+	return querier.PlaceholderAuditRecordsForExecution(testCaseExecutionUUID)
+}
+
+// recordPlaceholderAudit writes one record to the active sink.
+// Write failures are logged and never change the placeholder result.
+func recordPlaceholderAudit(input GoPlaceholderInput, runtime PlaceholderRuntime, value PlaceholderValue, err error) {
+	placeholderAuditSinkMutex.RLock()
+	sink := placeholderAuditSink
+	placeholderAuditSinkMutex.RUnlock()
+
+	if sink == nil {
+		return
+	}
+
+	record := PlaceholderAuditRecord{
+		Sequence:                    atomic.AddUint64(&placeholderAuditSequence, 1),
+		TestCaseExecutionUUID:       input.TestCaseExecutionUUID,
+		Placeholder:                 input.Placeholder,
+		FunctionName:                input.FunctionName,
+		ArrayIndexes:                append([]int{}, input.ArrayIndexes...),
+		Arguments:                   append([]string{}, input.Arguments...),
+		UseEntropyFromExecutionUUID: input.UseEntropyFromExecutionUUID,
+		ExtraEntropy:                input.ExtraEntropy,
+		Entropy:                     input.Entropy,
+		EntropyScheme:               input.EntropyScheme,
+		EntropyScope:                input.EntropyScope,
+		EntropyScopeIDs:             input.EntropyScopeIDs,
+		ClockTime:                   input.ClockTime,
+		TestDataDomainName:          input.TestDataDomainName,
+		TestDataValues:              maps.Clone(input.TestDataValues),
+		FieldPath:                   append([]string{}, input.FieldPath...),
+		Runtime:                     runtime,
+		LuaOutput:                   input.luaOutput.snapshot(),
+	}
+	if err == nil {
+		record.Output = value.Format()
+		record.OutputKind = value.kind()
+	}
+	if err != nil {
+		record.Error = err.Error()
+		record.ErrorKind = PlaceholderErrorKindOf(err)
+	}
+
+	if writeErr := sink.WritePlaceholderAuditRecord(record); writeErr != nil {
+		scriptEngineLogger().Error("failed to write placeholder audit record", logKeyFunction, input.FunctionName,
+			logKeyExecutionUUID, input.TestCaseExecutionUUID, "placeholder", input.Placeholder, "error", writeErr)
+	}
+}
+
+// InMemoryPlaceholderAuditSink keeps audit records in memory, grouped per execution UUID.
+type InMemoryPlaceholderAuditSink struct {
+	mutex               sync.RWMutex
+	recordsPerExecution map[string][]PlaceholderAuditRecord
+}
+
+// NewInMemoryPlaceholderAuditSink creates an empty in-memory audit sink.
+func NewInMemoryPlaceholderAuditSink() *InMemoryPlaceholderAuditSink {
+	return &InMemoryPlaceholderAuditSink{
+		recordsPerExecution: map[string][]PlaceholderAuditRecord{},
+	}
+}
+
+// WritePlaceholderAuditRecord stores one record.
+func (sink *InMemoryPlaceholderAuditSink) WritePlaceholderAuditRecord(record PlaceholderAuditRecord) error {
+	sink.mutex.Lock()
+	sink.recordsPerExecution[record.TestCaseExecutionUUID] = append(sink.recordsPerExecution[record.TestCaseExecutionUUID], record)
+	sink.mutex.Unlock()
+
+	return nil
+}
+
+// PlaceholderAuditRecordsForExecution returns a copy of the records for one execution in write order.
+func (sink *InMemoryPlaceholderAuditSink) PlaceholderAuditRecordsForExecution(testCaseExecutionUUID string) ([]PlaceholderAuditRecord, error) {
+	sink.mutex.RLock()
+	defer sink.mutex.RUnlock()
+
+	return append([]PlaceholderAuditRecord{}, sink.recordsPerExecution[testCaseExecutionUUID]...), nil
+}
+
+// Clear removes all stored records.
+func (sink *InMemoryPlaceholderAuditSink) Clear() {
+	sink.mutex.Lock()
+	sink.recordsPerExecution = map[string][]PlaceholderAuditRecord{}
+	sink.mutex.Unlock()
+}
+
+// JSONLinesPlaceholderAuditSink appends audit records as JSON Lines to a file.
+type JSONLinesPlaceholderAuditSink struct {
+	mutex    sync.Mutex
+	filePath string
+	file     *os.File
+}
+
+// NewJSONLinesPlaceholderAuditSink opens, or creates, a JSON Lines file for appending audit records.
+func NewJSONLinesPlaceholderAuditSink(filePath string) (*JSONLinesPlaceholderAuditSink, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open placeholder audit file '%s': %w", filePath, err)
+	}
+
+	return &JSONLinesPlaceholderAuditSink{
+		filePath: filePath,
+		file:     file,
+	}, nil
+}
+
+// WritePlaceholderAuditRecord appends one record as a single JSON line.
+func (sink *JSONLinesPlaceholderAuditSink) WritePlaceholderAuditRecord(record PlaceholderAuditRecord) error {
+	recordAsJson, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	if sink.file == nil {
+		return fmt.Errorf("placeholder audit file '%s' is closed", sink.filePath)
+	}
+
+	_, err = sink.file.Write(append(recordAsJson, '\n'))
+	return err
+}
+
+// PlaceholderAuditRecordsForExecution reads the file and returns the records for one execution in write order.
+func (sink *JSONLinesPlaceholderAuditSink) PlaceholderAuditRecordsForExecution(testCaseExecutionUUID string) ([]PlaceholderAuditRecord, error) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	file, err := os.Open(sink.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read placeholder audit file '%s': %w", sink.filePath, err)
+	}
+	defer file.Close()
+
+	var records []PlaceholderAuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record PlaceholderAuditRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid placeholder audit record on line %d in '%s': %w", lineNumber, sink.filePath, err)
+		}
+		if record.TestCaseExecutionUUID == testCaseExecutionUUID {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
+
+// Close closes the underlying file.
+func (sink *JSONLinesPlaceholderAuditSink) Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	if sink.file == nil {
+		return nil
+	}
+
+	err := sink.file.Close()
+	sink.file = nil
+	return err
+}
@@ -0,0 +1,153 @@
+package scriptEngine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func logPlaceholderAuditRecords(t *testing.T, callLabel string, records []PlaceholderAuditRecord) {
+	t.Helper()
+	for _, record := range records {
+		t.Logf(
+			"Audit record [%s]\n  Sequence: %d\n  Placeholder: %q\n  FunctionName: %q\n  Arguments: %v\n  Entropy: %d\n  ClockTime: %s\n  Runtime: %s\n  Output: %q\n  Error: %q",
+			callLabel,
+			record.Sequence,
+			record.Placeholder,
+			record.FunctionName,
+			record.Arguments,
+			record.Entropy,
+			record.ClockTime.Format(time.RFC3339Nano),
+			record.Runtime,
+			record.Output,
+			record.Error,
+		)
+	}
+}
+
+func executeAuditedRequests(t *testing.T, testCaseExecutionUUID string) {
+	t.Helper()
+
+	originalTimeProvider := currentTimeProvider
+	currentTimeProvider = func() time.Time {
+		return time.Date(2026, time.February, 26, 12, 30, 45, 0, time.Local)
+	}
+	defer func() {
+		currentTimeProvider = originalTimeProvider
+	}()
+
+	requests := []PlaceholderExecutionRequest{
+		{
+			Placeholder:                 "{{Fenix.TodayShiftDay(1)}}",
+			FunctionName:                "Fenix_TodayShiftDay",
+			Arguments:                   []string{"1"},
+			UseEntropyFromExecutionUUID: true,
+			ExtraEntropy:                3,
+			TestCaseExecutionUUID:       testCaseExecutionUUID,
+		},
+		{
+			Placeholder:           "{{Fenix.TodayShiftDay(x)}}",
+			FunctionName:          "Fenix_TodayShiftDay",
+			Arguments:             []string{"x"},
+			TestCaseExecutionUUID: testCaseExecutionUUID,
+		},
+		{
+			Placeholder:           "{{Fenix.TodayShiftDay(0)}}",
+			FunctionName:          "Fenix_TodayShiftDay",
+			Arguments:             []string{"0"},
+			TestCaseExecutionUUID: "other-execution",
+		},
+	}
+	for _, request := range requests {
+		logExecutionRequest(t, "audited-call", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "audited-call", value, err)
+	}
+}
+
+func assertAuditRecordsForExecution(t *testing.T, records []PlaceholderAuditRecord, testCaseExecutionUUID string) {
+	t.Helper()
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 audit records for execution, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.TestCaseExecutionUUID != testCaseExecutionUUID || first.FunctionName != "Fenix_TodayShiftDay" {
+		t.Fatalf("unexpected first record: %+v", first)
+	}
+	if first.Output != "2026-02-27" || first.Error != "" || first.Runtime != PlaceholderRuntimeGo {
+		t.Fatalf("unexpected first record result: %+v", first)
+	}
+	if first.UseEntropyFromExecutionUUID != true || first.ExtraEntropy != 3 || first.Entropy == 3 {
+		t.Fatalf("expected entropy inputs to be recorded, got %+v", first)
+	}
+	if first.ClockTime.Equal(time.Date(2026, time.February, 26, 12, 30, 45, 0, time.Local)) == false {
+		t.Fatalf("expected clock time to be recorded, got %s", first.ClockTime)
+	}
+
+	second := records[1]
+	if second.Error == "" || second.ErrorKind != PlaceholderErrorKindHandler {
+		t.Fatalf("expected second record to contain handler error, got %+v", second)
+	}
+	if second.Sequence <= first.Sequence {
+		t.Fatalf("expected increasing sequence numbers, got %d and %d", first.Sequence, second.Sequence)
+	}
+}
+
+func TestPlaceholderAudit_InMemorySinkShouldRecordEveryResolution(t *testing.T) {
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	testCaseExecutionUUID := "audit-in-memory-execution"
+	executeAuditedRequests(t, testCaseExecutionUUID)
+
+	records, err := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	logPlaceholderAuditRecords(t, "in-memory", records)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertAuditRecordsForExecution(t, records, testCaseExecutionUUID)
+}
+
+func TestPlaceholderAudit_JSONLinesSinkShouldRecordEveryResolution(t *testing.T) {
+	auditFilePath := filepath.Join(t.TempDir(), "placeholder-audit.jsonl")
+	sink, err := NewJSONLinesPlaceholderAuditSink(auditFilePath)
+	if err != nil {
+		t.Fatalf("failed to create JSON Lines sink: %v", err)
+	}
+	defer sink.Close()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	testCaseExecutionUUID := "audit-json-lines-execution"
+	executeAuditedRequests(t, testCaseExecutionUUID)
+
+	fileContent, err := os.ReadFile(auditFilePath)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+	t.Logf("Audit file content:\n%s", fileContent)
+	if strings.Count(string(fileContent), "\n") != 3 {
+		t.Fatalf("expected 3 JSON lines in audit file")
+	}
+
+	records, err := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	logPlaceholderAuditRecords(t, "json-lines", records)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	assertAuditRecordsForExecution(t, records, testCaseExecutionUUID)
+}
+
+func TestGetPlaceholderAuditRecords_ShouldFailWithoutSink(t *testing.T) {
+	SetPlaceholderAuditSink(nil)
+
+	_, err := GetPlaceholderAuditRecords("execution-uuid")
+	if err == nil {
+		t.Fatalf("expected error when no audit sink is configured")
+	}
+}
@@ -0,0 +1,198 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PlaceholderDispatchPolicy decides whether a function runs as Go handler or Lua function.
+type PlaceholderDispatchPolicy string
+
+const (
+	// PlaceholderDispatchGoFirst uses the Go handler when registered, otherwise Lua. This is the default.
+	PlaceholderDispatchGoFirst PlaceholderDispatchPolicy = "go-first"
+	// PlaceholderDispatchLuaFirst uses the Lua function when loaded, otherwise the Go handler.
+	PlaceholderDispatchLuaFirst PlaceholderDispatchPolicy = "lua-first"
+	// PlaceholderDispatchGoOnly only uses the Go handler.
+	PlaceholderDispatchGoOnly PlaceholderDispatchPolicy = "go-only"
+	// PlaceholderDispatchLuaOnly only uses the Lua function.
+	PlaceholderDispatchLuaOnly PlaceholderDispatchPolicy = "lua-only"
+)
+
+// PlaceholderDispatchPolicySource tells on which level the policy of a decision was configured.
+type PlaceholderDispatchPolicySource string
+
+const (
+	PlaceholderDispatchPolicySourceGlobal         PlaceholderDispatchPolicySource = "global"
+	PlaceholderDispatchPolicySourceFunction       PlaceholderDispatchPolicySource = "function"
+	PlaceholderDispatchPolicySourceTestDataDomain PlaceholderDispatchPolicySource = "testdata_domain"
+)
+
+// PlaceholderDispatchDecision describes how one function was resolved.
+type PlaceholderDispatchDecision struct {
+	FunctionName       string
+	TestDataDomainName string
+	Policy             PlaceholderDispatchPolicy
+	PolicySource       PlaceholderDispatchPolicySource
+	Runtime            PlaceholderRuntime
+	// True when a Go handler is registered and when a Lua function is loaded.
+	GoHandlerExists   bool
+	LuaFunctionExists bool
+}
+
+// maxLoggedPlaceholderDispatchDecisions bounds the number of decisions remembered for logging.
+const maxLoggedPlaceholderDispatchDecisions = 1000
+
+var (
+	placeholderDispatchPolicyMutex sync.RWMutex
+	// Policy used when neither the TestData domain nor the function has one.
+	globalPlaceholderDispatchPolicy = PlaceholderDispatchGoFirst
+	// Policies per function name.
+	functionPlaceholderDispatchPolicies = map[string]PlaceholderDispatchPolicy{}
+	// Policies per TestData domain name.
+	testDataDomainPlaceholderDispatchPolicies = map[string]PlaceholderDispatchPolicy{}
+
+	loggedPlaceholderDispatchDecisionsMutex sync.Mutex
+	// Last logged decision per function and TestData domain, so only new or changed decisions are logged.
+	loggedPlaceholderDispatchDecisions = map[string]PlaceholderDispatchDecision{}
+)
+
+// SetPlaceholderDispatchPolicy sets the global dispatch policy.
+func SetPlaceholderDispatchPolicy(policy PlaceholderDispatchPolicy) error {
+	if err := validatePlaceholderDispatchPolicy(policy); err != nil {
+		return err
+	}
+
+	placeholderDispatchPolicyMutex.Lock()
+	globalPlaceholderDispatchPolicy = policy
+	placeholderDispatchPolicyMutex.Unlock()
+
+	return nil
+}
+
+// SetFunctionDispatchPolicy sets the dispatch policy for one function. An empty policy removes it.
+func SetFunctionDispatchPolicy(functionName string, policy PlaceholderDispatchPolicy) error {
+	return setNamedPlaceholderDispatchPolicy(functionPlaceholderDispatchPolicies, "function name", functionName, policy)
+}
+
+// SetTestDataDomainDispatchPolicy sets the dispatch policy for all functions called for one TestData domain.
+// It takes precedence over function and global policies. An empty policy removes it.
+func SetTestDataDomainDispatchPolicy(testDataDomainName string, policy PlaceholderDispatchPolicy) error {
+	return setNamedPlaceholderDispatchPolicy(testDataDomainPlaceholderDispatchPolicies, "TestData domain name", testDataDomainName, policy)
+}
+
+// setNamedPlaceholderDispatchPolicy stores or removes one policy in a policy map.
+func setNamedPlaceholderDispatchPolicy(policies map[string]PlaceholderDispatchPolicy, nameDescription string, name string, policy PlaceholderDispatchPolicy) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%s can not be empty", nameDescription)
+	}
+
+	if policy != "" {
+		if err := validatePlaceholderDispatchPolicy(policy); err != nil {
+			return err
+		}
+	}
+
+	placeholderDispatchPolicyMutex.Lock()
+	defer placeholderDispatchPolicyMutex.Unlock()
+
+	if policy == "" {
+		delete(policies, name)
+		return nil
+	}
+	policies[name] = policy
+
+	return nil
+}
+
+// validatePlaceholderDispatchPolicy returns an error for unknown policies.
+func validatePlaceholderDispatchPolicy(policy PlaceholderDispatchPolicy) error {
+	switch policy {
+	case PlaceholderDispatchGoFirst, PlaceholderDispatchLuaFirst, PlaceholderDispatchGoOnly, PlaceholderDispatchLuaOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown dispatch policy '%s'", policy)
+	}
+}
+
+// resolvePlaceholderDispatchPolicy returns the policy for a call.
+// Precedence: TestData domain, function, global.
+func resolvePlaceholderDispatchPolicy(functionName string, testDataDomainName string) (PlaceholderDispatchPolicy, PlaceholderDispatchPolicySource) {
+	placeholderDispatchPolicyMutex.RLock()
+	defer placeholderDispatchPolicyMutex.RUnlock()
+
+	if policy, exists := testDataDomainPlaceholderDispatchPolicies[testDataDomainName]; exists == true && testDataDomainName != "" {
+		return policy, PlaceholderDispatchPolicySourceTestDataDomain
+	}
+	if policy, exists := functionPlaceholderDispatchPolicies[functionName]; exists == true {
+		return policy, PlaceholderDispatchPolicySourceFunction
+	}
+
+	return globalPlaceholderDispatchPolicy, PlaceholderDispatchPolicySourceGlobal
+}
+
+// ResolvePlaceholderDispatch returns the runtime that would be used for a function and TestData domain.
+func ResolvePlaceholderDispatch(functionName string, testDataDomainName string) PlaceholderDispatchDecision {
+	policy, policySource := resolvePlaceholderDispatchPolicy(functionName, testDataDomainName)
+	_, goHandlerExists := lookupGoPlaceholderFunction(functionName)
+	luaFunctionExists := luaPlaceholderFunctionExists(functionName)
+
+	decision := PlaceholderDispatchDecision{
+		FunctionName:       functionName,
+		TestDataDomainName: testDataDomainName,
+		Policy:             policy,
+		PolicySource:       policySource,
+		GoHandlerExists:    goHandlerExists,
+		LuaFunctionExists:  luaFunctionExists,
+	}
+
+	switch policy {
+	case PlaceholderDispatchGoOnly:
+		decision.Runtime = PlaceholderRuntimeGo
+	case PlaceholderDispatchLuaOnly:
+		decision.Runtime = PlaceholderRuntimeLua
+	case PlaceholderDispatchLuaFirst:
+		decision.Runtime = PlaceholderRuntimeLua
+		if luaFunctionExists == false && goHandlerExists == true {
+			decision.Runtime = PlaceholderRuntimeGo
+		}
+	default:
+		decision.Runtime = PlaceholderRuntimeLua
+		if goHandlerExists == true {
+			decision.Runtime = PlaceholderRuntimeGo
+		}
+	}
+
+	return decision
+}
+
+// logPlaceholderDispatchDecision logs a decision the first time it is made and every time it changes.
+// When maxLoggedPlaceholderDispatchDecisions are remembered they are forgotten and logged again when next made,
+// so calls with many different function or domain names don't grow the memory without bound.
+func logPlaceholderDispatchDecision(decision PlaceholderDispatchDecision, testCaseExecutionUUID string) {
+	decisionKey := decision.TestDataDomainName + "\x00" + decision.FunctionName
+
+	loggedPlaceholderDispatchDecisionsMutex.Lock()
+	previousDecision, exists := loggedPlaceholderDispatchDecisions[decisionKey]
+	if exists == false && len(loggedPlaceholderDispatchDecisions) >= maxLoggedPlaceholderDispatchDecisions {
+		clear(loggedPlaceholderDispatchDecisions)
+	}
+	loggedPlaceholderDispatchDecisions[decisionKey] = decision
+	loggedPlaceholderDispatchDecisionsMutex.Unlock()
+
+	if exists == true && previousDecision == decision {
+		return
+	}
+
+	scriptEngineLogger().Info("placeholder dispatch decision",
+		logKeyFunction, decision.FunctionName,
+		logKeyExecutionUUID, testCaseExecutionUUID,
+		"testDataDomain", decision.TestDataDomainName,
+		"runtime", decision.Runtime,
+		"policy", decision.Policy,
+		"policySource", decision.PolicySource,
+		"goHandler", decision.GoHandlerExists,
+		"luaFunction", decision.LuaFunctionExists)
+}
@@ -0,0 +1,165 @@
+package scriptEngine
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// dispatchPolicyTestLuaScript is a domain Lua override of a function that also has a Go handler.
+const dispatchPolicyTestLuaScript = `
+function Fenix_TodayShiftDay(inputTable)
+    return { success = true, value = "lua-override", errorMessage = "" }
+end
+`
+
+func resetPlaceholderDispatchPolicies() {
+	placeholderDispatchPolicyMutex.Lock()
+	globalPlaceholderDispatchPolicy = PlaceholderDispatchGoFirst
+	functionPlaceholderDispatchPolicies = map[string]PlaceholderDispatchPolicy{}
+	testDataDomainPlaceholderDispatchPolicies = map[string]PlaceholderDispatchPolicy{}
+	placeholderDispatchPolicyMutex.Unlock()
+}
+
+func TestPlaceholderDispatchPolicy_ShouldResolveDomainFunctionAndGlobalPolicies(t *testing.T) {
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{{LuaScriptName: "domainOverride", LuaScript: []byte(dispatchPolicyTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+	defer resetPlaceholderDispatchPolicies()
+
+	if err := SetFunctionDispatchPolicy("Fenix_TodayShiftDay", PlaceholderDispatchLuaFirst); err != nil {
+		t.Fatalf("failed to set function policy: %v", err)
+	}
+	if err := SetTestDataDomainDispatchPolicy("Go_Domain", PlaceholderDispatchGoOnly); err != nil {
+		t.Fatalf("failed to set domain policy: %v", err)
+	}
+
+	testCases := []struct {
+		name               string
+		testDataDomainName string
+		expectedRuntime    PlaceholderRuntime
+		expectedSource     PlaceholderDispatchPolicySource
+		expectedLuaValue   bool
+	}{
+		{name: "function policy lua-first", expectedRuntime: PlaceholderRuntimeLua, expectedSource: PlaceholderDispatchPolicySourceFunction, expectedLuaValue: true},
+		{name: "domain policy overrides function policy", testDataDomainName: "Go_Domain", expectedRuntime: PlaceholderRuntimeGo, expectedSource: PlaceholderDispatchPolicySourceTestDataDomain},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			decision := ResolvePlaceholderDispatch("Fenix_TodayShiftDay", testCase.testDataDomainName)
+			t.Logf("Output [%s]\n  Decision: %+v", testCase.name, decision)
+			if decision.Runtime != testCase.expectedRuntime || decision.PolicySource != testCase.expectedSource {
+				t.Fatalf("unexpected decision: %+v", decision)
+			}
+
+			request := PlaceholderExecutionRequest{
+				FunctionName:       "Fenix_TodayShiftDay",
+				Arguments:          []string{"0"},
+				TestDataDomainName: testCase.testDataDomainName,
+			}
+			logExecutionRequest(t, testCase.name, request)
+			value, err := ExecutePlaceholder(request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if (value == "lua-override") != testCase.expectedLuaValue {
+				t.Fatalf("unexpected value for runtime %s: %q", testCase.expectedRuntime, value)
+			}
+		})
+	}
+
+	if err := SetFunctionDispatchPolicy("Fenix_TodayShiftDay", ""); err != nil {
+		t.Fatalf("failed to remove function policy: %v", err)
+	}
+	if decision := ResolvePlaceholderDispatch("Fenix_TodayShiftDay", ""); decision.Runtime != PlaceholderRuntimeGo || decision.PolicySource != PlaceholderDispatchPolicySourceGlobal {
+		t.Fatalf("expected global go-first after removing the function policy, got %+v", decision)
+	}
+
+	if err := SetPlaceholderDispatchPolicy(PlaceholderDispatchLuaOnly); err != nil {
+		t.Fatalf("failed to set global policy: %v", err)
+	}
+	value, _ := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}})
+	if value != "lua-override" {
+		t.Fatalf("expected global lua-only to use the Lua override, got %q", value)
+	}
+}
+
+func TestPlaceholderDispatchPolicy_ShouldFailOrFallBackWhenRuntimeIsMissing(t *testing.T) {
+	CloseDownLuaScriptEngine()
+	defer resetPlaceholderDispatchPolicies()
+
+	if err := SetFunctionDispatchPolicy("Fenix_TodayShiftDay", PlaceholderDispatchLuaFirst); err != nil {
+		t.Fatalf("failed to set function policy: %v", err)
+	}
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}})
+	logPlaceholderExecutionResult(t, "lua-first-fallback", value, err)
+	if err != nil {
+		t.Fatalf("expected lua-first to fall back to Go, got: %v", err)
+	}
+
+	if err = SetFunctionDispatchPolicy("HappyLuaTime", PlaceholderDispatchGoOnly); err != nil {
+		t.Fatalf("failed to set function policy: %v", err)
+	}
+	value, err = ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "HappyLuaTime"})
+	logPlaceholderExecutionResult(t, "go-only-without-handler", value, err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindFunctionNotFound {
+		t.Fatalf("expected function_not_found for go-only without Go handler, got: %v", err)
+	}
+
+	if err = SetPlaceholderDispatchPolicy("random"); err == nil {
+		t.Fatalf("expected error for unknown global policy")
+	}
+	if err = SetFunctionDispatchPolicy("", PlaceholderDispatchGoOnly); err == nil {
+		t.Fatalf("expected error for empty function name")
+	}
+	if err = SetTestDataDomainDispatchPolicy("Some_Domain", "random"); err == nil {
+		t.Fatalf("expected error for unknown domain policy")
+	}
+}
+
+func TestPlaceholderDispatchPolicy_ShouldLogNewAndChangedDecisions(t *testing.T) {
+	var logBuffer bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&logBuffer, nil)))
+	defer SetLogger(nil)
+	defer resetPlaceholderDispatchPolicies()
+
+	request := PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}, TestDataDomainName: "Logged_Domain"}
+	_, _ = ExecutePlaceholder(request)
+	_, _ = ExecutePlaceholder(request)
+	if err := SetTestDataDomainDispatchPolicy("Logged_Domain", PlaceholderDispatchGoOnly); err != nil {
+		t.Fatalf("failed to set domain policy: %v", err)
+	}
+	_, _ = ExecutePlaceholder(request)
+
+	t.Logf("Output [dispatch-log]\n%s", logBuffer.String())
+	loggedDecisions := strings.Count(logBuffer.String(), `msg="placeholder dispatch decision" function=Fenix_TodayShiftDay executionUuid="" testDataDomain=Logged_Domain`)
+	if loggedDecisions != 2 {
+		t.Fatalf("expected 2 logged decisions (first and changed), got %d", loggedDecisions)
+	}
+}
+
+func TestPlaceholderDispatchPolicy_ShouldBoundRememberedDecisions(t *testing.T) {
+	var logBuffer bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&logBuffer, nil)))
+	defer SetLogger(nil)
+
+	for functionIndex := 0; functionIndex <= maxLoggedPlaceholderDispatchDecisions; functionIndex++ {
+		logPlaceholderDispatchDecision(ResolvePlaceholderDispatch(fmt.Sprintf("Generated_Function_%d", functionIndex), "Bounded_Domain"), "")
+	}
+
+	loggedPlaceholderDispatchDecisionsMutex.Lock()
+	rememberedDecisions := len(loggedPlaceholderDispatchDecisions)
+	loggedPlaceholderDispatchDecisionsMutex.Unlock()
+
+	t.Logf("Output [dispatch-log-bound]\n  Remembered decisions: %d\n  Logged decisions: %d",
+		rememberedDecisions, strings.Count(logBuffer.String(), `msg="placeholder dispatch decision"`))
+	if rememberedDecisions > maxLoggedPlaceholderDispatchDecisions {
+		t.Fatalf("expected at most %d remembered decisions, got %d", maxLoggedPlaceholderDispatchDecisions, rememberedDecisions)
+	}
+}
@@ -2,10 +2,10 @@ package scriptEngine
 
 import (
 	"fmt"
-	"hash/crc32"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type GoPlaceholderInput struct {
@@ -23,24 +23,60 @@ type GoPlaceholderInput struct {
 	ExtraEntropy uint64
 	// Final entropy used by handlers after UUID hash + extra entropy.
 	Entropy uint64
+	// Scheme used to derive Entropy and the per-index seeds. Empty means legacy.
+	EntropyScheme EntropyScheme
+	// Entropy scope of the call and the resolved scope IDs. An empty scope keeps execution UUID entropy only.
+	EntropyScope    EntropyScope
+	EntropyScopeIDs EntropyScopeIDs
 	// Original execution UUID used for deterministic entropy generation.
 	TestCaseExecutionUUID string
+	// Clock instant used by date/time handlers, resolved once per call by the dispatcher
+	// from the execution clock and time zone.
+	ClockTime time.Time
+	// TestData domain of the call, from the request or the started execution.
+	TestDataDomainName string
+	// TestData values of the rendering, per column data name.
+	TestDataValues map[string]string
+	// Optional field path applied to a structured result, for example ["zip"] for {{Fenix.Address().zip}}.
+	FieldPath []string
+	// Lua print and log output of the call.
+	luaOutput *luaOutputCapture
+	// Store of the started execution, see ExecutionStore.
+	executionStore *PlaceholderExecutionStore
 }
 
 type GoPlaceholderFunction func(input GoPlaceholderInput) (string, error)
 
+// GoPlaceholderValueFunction is a Go handler that returns a typed value, such as a number, list or record.
+type GoPlaceholderValueFunction func(input GoPlaceholderInput) (PlaceholderValue, error)
+
 var (
 	goPlaceholderFunctionsMutex sync.RWMutex
 	// Global registry used by ExecuteLuaScriptBasedOnPlaceholder for Go-first dispatch.
-	goPlaceholderFunctions = map[string]GoPlaceholderFunction{}
+	goPlaceholderFunctions = map[string]GoPlaceholderValueFunction{}
 )
 
 // RegisterGoPlaceholderFunction registers or replaces a Go handler for a function name.
 func RegisterGoPlaceholderFunction(functionName string, fn GoPlaceholderFunction) error {
+	if fn == nil {
+		return fmt.Errorf("go placeholder function for '%s' is nil", strings.TrimSpace(functionName))
+	}
+
+	return RegisterGoPlaceholderValueFunction(functionName, func(input GoPlaceholderInput) (PlaceholderValue, error) {
+		value, err := fn(input)
+		return StringValue(value), err
+	})
+}
+
+// RegisterGoPlaceholderValueFunction registers or replaces a Go handler that returns a typed value.
+func RegisterGoPlaceholderValueFunction(functionName string, fn GoPlaceholderValueFunction) error {
 	functionName = strings.TrimSpace(functionName)
 	if functionName == "" {
 		return fmt.Errorf("function name can not be empty")
 	}
+	if functionName == placeholderMetricsUnknownFunctionName {
+		return fmt.Errorf("function name '%s' is reserved for calls to functions that don't exist", functionName)
+	}
 	if fn == nil {
 		return fmt.Errorf("go placeholder function for '%s' is nil", functionName)
 	}
@@ -52,6 +88,15 @@ func RegisterGoPlaceholderFunction(functionName string, fn GoPlaceholderFunction
 	return nil
 }
 
+// lookupGoPlaceholderFunction returns the registered Go handler for a function name.
+func lookupGoPlaceholderFunction(functionName string) (goFunction GoPlaceholderValueFunction, exists bool) {
+	goPlaceholderFunctionsMutex.RLock()
+	goFunction, exists = goPlaceholderFunctions[functionName]
+	goPlaceholderFunctionsMutex.RUnlock()
+
+	return goFunction, exists
+}
+
 // executeGoPlaceholderFunction attempts to route a placeholder call to a registered Go function.
 // Returns handled=false when no Go handler is registered, allowing Lua fallback.
 func executeGoPlaceholderFunction(inputParameterArray []interface{}, testCaseExecutionUuid string) (responseValue string, handled bool, err error) {
@@ -60,9 +105,7 @@ func executeGoPlaceholderFunction(inputParameterArray []interface{}, testCaseExe
 		return "", false, nil
 	}
 
-	goPlaceholderFunctionsMutex.RLock()
-	goFunction, exists := goPlaceholderFunctions[functionName]
-	goPlaceholderFunctionsMutex.RUnlock()
+	goFunction, exists := lookupGoPlaceholderFunction(functionName)
 	if exists == false {
 		return "", false, nil
 	}
@@ -72,8 +115,8 @@ func executeGoPlaceholderFunction(inputParameterArray []interface{}, testCaseExe
 		return "", true, err
 	}
 
-	responseValue, err = goFunction(parsedInput)
-	return responseValue, true, err
+	value, err := callGoPlaceholderFunction(goFunction, parsedInput)
+	return value.Format(), true, err
 }
 
 // tryExtractFunctionName returns the canonical function name from parsed placeholder input.
@@ -89,37 +132,55 @@ func tryExtractFunctionName(inputParameterArray []interface{}) (functionName str
 // parseGoPlaceholderInput converts the shared placeholder input format into a typed structure
 // used by all Go handlers.
 func parseGoPlaceholderInput(inputParameterArray []interface{}, testCaseExecutionUuid string) (goInput GoPlaceholderInput, err error) {
+	request, err := parseLegacyPlaceholderRequest(inputParameterArray, testCaseExecutionUuid)
+	if err != nil {
+		return goInput, err
+	}
+
+	return newGoPlaceholderInput(request)
+}
+
+// ParsePlaceholderExecutionRequest converts the legacy positional input into a typed request,
+// so callers can add request fields before calling ExecutePlaceholder.
+func ParsePlaceholderExecutionRequest(inputParameterArray []interface{}, testCaseExecutionUuid string) (PlaceholderExecutionRequest, error) {
+	return parseLegacyPlaceholderRequest(inputParameterArray, testCaseExecutionUuid)
+}
+
+// parseLegacyPlaceholderRequest validates the legacy positional input
+// [placeholder, functionName, arrayIndexes, arguments, useEntropy, extraEntropy, (optional) entropyScope, (optional) fieldPath]
+// and converts it into a PlaceholderExecutionRequest without ever panicking.
+func parseLegacyPlaceholderRequest(inputParameterArray []interface{}, testCaseExecutionUuid string) (request PlaceholderExecutionRequest, err error) {
 	if len(inputParameterArray) < 6 {
-		return goInput, fmt.Errorf("expected at least 6 input parameters, got %d", len(inputParameterArray))
+		return request, newLegacyInputError(fmt.Errorf("expected at least 6 input parameters, got %d", len(inputParameterArray)))
 	}
 
 	placeholder, ok := inputParameterArray[0].(string)
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 0 ('placeholder') must be a string")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 0 ('placeholder') must be a string"))
 	}
 
 	functionName, ok := inputParameterArray[1].(string)
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 1 ('functionName') must be a string")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 1 ('functionName') must be a string"))
 	}
 
 	arrayIndexesRaw, ok := inputParameterArray[2].([]interface{})
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 2 ('arrayIndexes') must be []interface{}")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 2 ('arrayIndexes') must be []interface{}"))
 	}
 
 	arrayIndexes := make([]int, 0, len(arrayIndexesRaw))
 	for _, rawIndex := range arrayIndexesRaw {
 		index, ok := rawIndex.(int)
 		if ok == false {
-			return goInput, fmt.Errorf("all array indexes must be integers")
+			return request, newLegacyInputError(fmt.Errorf("all array indexes must be integers"))
 		}
 		arrayIndexes = append(arrayIndexes, index)
 	}
 
 	argumentsRaw, ok := inputParameterArray[3].([]interface{})
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 3 ('arguments') must be []interface{}")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 3 ('arguments') must be []interface{}"))
 	}
 
 	arguments := make([]string, 0, len(argumentsRaw))
@@ -129,31 +190,59 @@ func parseGoPlaceholderInput(inputParameterArray []interface{}, testCaseExecutio
 
 	useEntropyFromExecutionUuid, ok := inputParameterArray[4].(bool)
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 4 ('useEntropyFromExecutionUuid') must be bool")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 4 ('useEntropyFromExecutionUuid') must be bool"))
 	}
 
 	extraEntropy, ok := inputParameterArray[5].(uint64)
 	if ok == false {
-		return goInput, fmt.Errorf("input parameter 5 ('extraEntropy') must be uint64")
+		return request, newLegacyInputError(fmt.Errorf("input parameter 5 ('extraEntropy') must be uint64"))
 	}
 
-	entropy := extraEntropy
-	if useEntropyFromExecutionUuid == true {
-		entropy = uint64(crc32.ChecksumIEEE([]byte(testCaseExecutionUuid))) + extraEntropy
+	entropyScope := EntropyScopeNone
+	if len(inputParameterArray) > 6 {
+		entropyScopeName, ok := inputParameterArray[6].(string)
+		if ok == false {
+			return request, newLegacyInputError(fmt.Errorf("input parameter 6 ('entropyScope') must be a string"))
+		}
+		entropyScope, err = ParseEntropyScope(entropyScopeName)
+		if err != nil {
+			return request, newLegacyInputError(err)
+		}
 	}
 
-	goInput = GoPlaceholderInput{
+	var fieldPath []string
+	if len(inputParameterArray) > 7 {
+		fieldPathRaw, ok := inputParameterArray[7].([]interface{})
+		if ok == false {
+			return request, newLegacyInputError(fmt.Errorf("input parameter 7 ('fieldPath') must be []interface{}"))
+		}
+		for _, rawField := range fieldPathRaw {
+			field, ok := rawField.(string)
+			if ok == false {
+				return request, newLegacyInputError(fmt.Errorf("all field path elements must be strings"))
+			}
+			fieldPath = append(fieldPath, field)
+		}
+	}
+
+	request = PlaceholderExecutionRequest{
 		Placeholder:                 placeholder,
 		FunctionName:                functionName,
 		ArrayIndexes:                arrayIndexes,
-		Arguments:                   normalizeArguments(arguments),
+		Arguments:                   arguments,
 		UseEntropyFromExecutionUUID: useEntropyFromExecutionUuid,
 		ExtraEntropy:                extraEntropy,
-		Entropy:                     entropy,
+		EntropyScope:                entropyScope,
 		TestCaseExecutionUUID:       testCaseExecutionUuid,
+		FieldPath:                   fieldPath,
 	}
 
-	return goInput, nil
+	return request, nil
+}
+
+// newLegacyInputError marks a legacy input validation failure as invalid input.
+func newLegacyInputError(err error) error {
+	return newPlaceholderExecutionError(PlaceholderErrorKindInvalidInput, "", err)
 }
 
 // normalizeArguments trims each argument and treats a single empty token as "no arguments".
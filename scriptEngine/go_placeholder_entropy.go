@@ -0,0 +1,166 @@
+package scriptEngine
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"sync"
+)
+
+// EntropyScheme selects how execution UUID, function name, array index and extra entropy become RNG seeds.
+type EntropyScheme string
+
+const (
+	// EntropySchemeLegacy uses entropy = crc32(uuid) + extraEntropy and seed = arrayIndex + entropy.
+	// Different inputs can give the same seed, for example index 2 with entropy 5 and index 3 with entropy 4.
+	EntropySchemeLegacy EntropyScheme = "legacy"
+	// EntropySchemeHMACSHA256V1 derives every seed with HMAC-SHA256 over
+	// (uuid, function name, array index, extra entropy), so all combinations give independent streams.
+	EntropySchemeHMACSHA256V1 EntropyScheme = "hmac-sha256-v1"
+)
+
+// entropySchemeV1Key separates v1 seeds from any other use of HMAC-SHA256 over the same fields.
+var entropySchemeV1Key = []byte("FenixScriptEngine/entropy/v1")
+
+var (
+	defaultEntropySchemeMutex sync.RWMutex
+	// Scheme used when neither the request nor the started execution selects one.
+	defaultEntropyScheme = EntropySchemeLegacy
+)
+
+// SetDefaultEntropyScheme sets the scheme used when neither the request nor the started execution selects one.
+func SetDefaultEntropyScheme(scheme EntropyScheme) error {
+	if err := validateEntropyScheme(scheme); err != nil {
+		return err
+	}
+
+	defaultEntropySchemeMutex.Lock()
+	defaultEntropyScheme = scheme
+	defaultEntropySchemeMutex.Unlock()
+
+	return nil
+}
+
+// DefaultEntropyScheme returns the scheme used when neither the request nor the started execution selects one.
+func DefaultEntropyScheme() EntropyScheme {
+	defaultEntropySchemeMutex.RLock()
+	defer defaultEntropySchemeMutex.RUnlock()
+
+	return defaultEntropyScheme
+}
+
+// validateEntropyScheme returns an error for unknown schemes.
+func validateEntropyScheme(scheme EntropyScheme) error {
+	switch scheme {
+	case EntropySchemeLegacy, EntropySchemeHMACSHA256V1:
+		return nil
+	default:
+		return fmt.Errorf("unknown entropy scheme '%s'", scheme)
+	}
+}
+
+// resolveEntropyScheme returns the request scheme, the execution scheme or the default scheme, in that order.
+func resolveEntropyScheme(executionContext *placeholderExecutionContext, requestScheme EntropyScheme) (EntropyScheme, error) {
+	if requestScheme != "" {
+		return requestScheme, validateEntropyScheme(requestScheme)
+	}
+	if executionContext != nil && executionContext.entropyScheme != "" {
+		return executionContext.entropyScheme, nil
+	}
+
+	return DefaultEntropyScheme(), nil
+}
+
+// entropyParameters are the values every entropy scheme derives seeds from.
+type entropyParameters struct {
+	testCaseExecutionUUID       string
+	useEntropyFromExecutionUUID bool
+	functionName                string
+	extraEntropy                uint64
+	entropyScope                EntropyScope
+	entropyScopeIDs             EntropyScopeIDs
+}
+
+// entropyParameters returns the entropy values of one call.
+func (input GoPlaceholderInput) entropyParameters() entropyParameters {
+	return entropyParameters{
+		testCaseExecutionUUID:       input.TestCaseExecutionUUID,
+		useEntropyFromExecutionUUID: input.UseEntropyFromExecutionUUID,
+		functionName:                input.FunctionName,
+		extraEntropy:                input.ExtraEntropy,
+		entropyScope:                input.EntropyScope,
+		entropyScopeIDs:             input.EntropyScopeIDs,
+	}
+}
+
+// seedForArrayIndex returns the RNG seed a handler uses for one array index.
+func (input GoPlaceholderInput) seedForArrayIndex(arrayIndex int) int64 {
+	return deriveEntropySeed(input.EntropyScheme, input.Entropy, input.entropyParameters(), arrayIndex)
+}
+
+// deriveEntropy returns the call entropy. Legacy gives crc32(uuid) + extraEntropy, or crc32(scope key) + extraEntropy
+// when an entropy scope is used. V1 gives an HMAC value without array index, limited to 53 bits so Lua numbers hold it exactly.
+func deriveEntropy(scheme EntropyScheme, parameters entropyParameters) uint64 {
+	if scheme == EntropySchemeHMACSHA256V1 {
+		return hmacEntropySum(parameters, false, 0) & (1<<53 - 1)
+	}
+
+	if scopeKey := parameters.scopeKey(); scopeKey != "" {
+		return uint64(crc32.ChecksumIEEE([]byte(scopeKey))) + parameters.extraEntropy
+	}
+
+	if parameters.useEntropyFromExecutionUUID == true {
+		return uint64(crc32.ChecksumIEEE([]byte(parameters.testCaseExecutionUUID))) + parameters.extraEntropy
+	}
+
+	return parameters.extraEntropy
+}
+
+// deriveEntropySeed returns the RNG seed for one array index.
+// The legacy scheme keeps seed = arrayIndex + entropy so existing expected values stay stable.
+func deriveEntropySeed(scheme EntropyScheme, entropy uint64, parameters entropyParameters, arrayIndex int) int64 {
+	if scheme == EntropySchemeHMACSHA256V1 {
+		return int64(hmacEntropySum(parameters, true, arrayIndex))
+	}
+
+	return int64(arrayIndex) + int64(entropy)
+}
+
+// hmacEntropySum returns the first 8 bytes of HMAC-SHA256 over length-prefixed entropy fields.
+// The execution UUID only contributes when UUID entropy is enabled, as in the legacy scheme.
+// The scope key is appended only when a scope is used, so unscoped seeds are unchanged.
+func hmacEntropySum(parameters entropyParameters, withArrayIndex bool, arrayIndex int) uint64 {
+	mac := hmac.New(sha256.New, entropySchemeV1Key)
+
+	writeField := func(field []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		mac.Write(length[:])
+		mac.Write(field)
+	}
+	writeNumber := func(number uint64) {
+		var field [8]byte
+		binary.BigEndian.PutUint64(field[:], number)
+		writeField(field[:])
+	}
+
+	testCaseExecutionUUID := ""
+	if parameters.useEntropyFromExecutionUUID == true {
+		testCaseExecutionUUID = parameters.testCaseExecutionUUID
+	}
+	writeField([]byte(testCaseExecutionUUID))
+	writeField([]byte(parameters.functionName))
+	if withArrayIndex == true {
+		writeNumber(uint64(int64(arrayIndex)))
+	} else {
+		writeField(nil)
+	}
+	writeNumber(parameters.extraEntropy)
+	if scopeKey := parameters.scopeKey(); scopeKey != "" {
+		writeField([]byte(scopeKey))
+	}
+
+	return binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+}
@@ -0,0 +1,129 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntropyScope selects the level whose identity is mixed into the entropy of a placeholder.
+// Scopes are nested: suite → test case → step → template instance.
+type EntropyScope string
+
+const (
+	// EntropyScopeNone keeps the entropy of the execution UUID only.
+	EntropyScopeNone EntropyScope = ""
+	// EntropyScopeSuite gives values per test suite.
+	EntropyScopeSuite EntropyScope = "suite"
+	// EntropyScopeTestCase gives values per test case.
+	EntropyScopeTestCase EntropyScope = "testcase"
+	// EntropyScopeStep gives values per test step.
+	EntropyScopeStep EntropyScope = "step"
+	// EntropyScopeTemplate gives values per rendered template instance.
+	EntropyScopeTemplate EntropyScope = "template"
+)
+
+// entropyScopeLevels lists the scopes from the outermost to the innermost.
+var entropyScopeLevels = []EntropyScope{EntropyScopeSuite, EntropyScopeTestCase, EntropyScopeStep, EntropyScopeTemplate}
+
+// EntropyScopeIDs identifies the current suite, test case, step and template instance.
+type EntropyScopeIDs struct {
+	SuiteID            string `json:"suiteId,omitempty"`
+	TestCaseID         string `json:"testCaseId,omitempty"`
+	StepID             string `json:"stepId,omitempty"`
+	TemplateInstanceID string `json:"templateInstanceId,omitempty"`
+}
+
+// ParseEntropyScope converts a scope name from the placeholder entropy tail, for example "step".
+func ParseEntropyScope(scopeName string) (EntropyScope, error) {
+	scope := EntropyScope(strings.ToLower(strings.TrimSpace(scopeName)))
+	if scope == EntropyScopeNone {
+		return scope, nil
+	}
+	for _, level := range entropyScopeLevels {
+		if scope == level {
+			return scope, nil
+		}
+	}
+
+	return EntropyScopeNone, fmt.Errorf("unknown entropy scope '%s'", scopeName)
+}
+
+// id returns the identity of one scope level.
+func (scopeIDs EntropyScopeIDs) id(scope EntropyScope) string {
+	switch scope {
+	case EntropyScopeSuite:
+		return scopeIDs.SuiteID
+	case EntropyScopeTestCase:
+		return scopeIDs.TestCaseID
+	case EntropyScopeStep:
+		return scopeIDs.StepID
+	case EntropyScopeTemplate:
+		return scopeIDs.TemplateInstanceID
+	default:
+		return ""
+	}
+}
+
+// overriddenBy returns the scope IDs with every non-empty ID in overrides replacing the current one.
+func (scopeIDs EntropyScopeIDs) overriddenBy(overrides EntropyScopeIDs) EntropyScopeIDs {
+	if overrides.SuiteID != "" {
+		scopeIDs.SuiteID = overrides.SuiteID
+	}
+	if overrides.TestCaseID != "" {
+		scopeIDs.TestCaseID = overrides.TestCaseID
+	}
+	if overrides.StepID != "" {
+		scopeIDs.StepID = overrides.StepID
+	}
+	if overrides.TemplateInstanceID != "" {
+		scopeIDs.TemplateInstanceID = overrides.TemplateInstanceID
+	}
+
+	return scopeIDs
+}
+
+// resolveEntropyScopeIDs merges the execution scope IDs with the request scope IDs.
+// The test case ID defaults to the execution UUID.
+func resolveEntropyScopeIDs(executionContext *placeholderExecutionContext, testCaseExecutionUUID string, requestScopeIDs EntropyScopeIDs) EntropyScopeIDs {
+	scopeIDs := EntropyScopeIDs{TestCaseID: testCaseExecutionUUID}
+	if executionContext != nil {
+		scopeIDs = scopeIDs.overriddenBy(executionContext.currentEntropyScopeIDs())
+	}
+
+	return scopeIDs.overriddenBy(requestScopeIDs)
+}
+
+// validateEntropyScope checks that the scope is known and that its own level has an ID.
+func validateEntropyScope(scope EntropyScope, scopeIDs EntropyScopeIDs) error {
+	if _, err := ParseEntropyScope(string(scope)); err != nil {
+		return err
+	}
+	if scope != EntropyScopeNone && scopeIDs.id(scope) == "" {
+		return fmt.Errorf("entropy scope '%s' is used but no %s ID is set", scope, scope)
+	}
+
+	return nil
+}
+
+// scopeKey returns the identity mixed into the entropy, or "" when no scope is used.
+// It contains the IDs from the suite down to the selected scope, and the execution UUID when UUID entropy is enabled.
+func (parameters entropyParameters) scopeKey() string {
+	if parameters.entropyScope == EntropyScopeNone {
+		return ""
+	}
+
+	var keyParts []string
+	if parameters.useEntropyFromExecutionUUID == true {
+		keyParts = append(keyParts, fmt.Sprintf("execution=%q", parameters.testCaseExecutionUUID))
+	}
+	for _, level := range entropyScopeLevels {
+		if scopeID := parameters.entropyScopeIDs.id(level); scopeID != "" {
+			keyParts = append(keyParts, fmt.Sprintf("%s=%q", level, scopeID))
+		}
+		if level == parameters.entropyScope {
+			break
+		}
+	}
+
+	return strings.Join(keyParts, "/")
+}
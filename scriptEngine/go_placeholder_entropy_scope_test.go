@@ -0,0 +1,163 @@
+package scriptEngine
+
+import (
+	"testing"
+)
+
+func scopedDecimalRequest(testCaseExecutionUUID string, entropyScope EntropyScope) PlaceholderExecutionRequest {
+	return PlaceholderExecutionRequest{
+		Placeholder:                 "{{Fenix.RandomPositiveDecimalValue(6, 2, 6, 2, .)}(true, 0, scope=" + string(entropyScope) + ")}}",
+		FunctionName:                "Fenix_RandomPositiveDecimalValue",
+		Arguments:                   []string{"6", "2", "6", "2", "."},
+		UseEntropyFromExecutionUUID: true,
+		EntropyScope:                entropyScope,
+		TestCaseExecutionUUID:       testCaseExecutionUUID,
+	}
+}
+
+func TestExecutePlaceholder_StepScopeShouldGiveDistinctValuesPerStep(t *testing.T) {
+	testCaseExecutionUUID := "entropy-scope-step-execution"
+	if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{
+		EntropyScopeIDs: EntropyScopeIDs{SuiteID: "suite-1"},
+	}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution(testCaseExecutionUUID)
+
+	executeInStep := func(stepID string, entropyScope EntropyScope) string {
+		if err := SetPlaceholderExecutionStep(testCaseExecutionUUID, stepID); err != nil {
+			t.Fatalf("failed to set step: %v", err)
+		}
+		request := scopedDecimalRequest(testCaseExecutionUUID, entropyScope)
+		logExecutionRequest(t, stepID, request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, stepID, value, err)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		return value
+	}
+
+	step1Value := executeInStep("step-1", EntropyScopeStep)
+	step2Value := executeInStep("step-2", EntropyScopeStep)
+	step1ValueAgain := executeInStep("step-1", EntropyScopeStep)
+	unscopedStep1Value := executeInStep("step-1", EntropyScopeNone)
+	unscopedStep2Value := executeInStep("step-2", EntropyScopeNone)
+
+	if step1Value == step2Value {
+		t.Fatalf("expected distinct values per step, got %q twice", step1Value)
+	}
+	if step1Value != step1ValueAgain {
+		t.Fatalf("expected reproducible value within one step, got %q and %q", step1Value, step1ValueAgain)
+	}
+	if unscopedStep1Value != unscopedStep2Value {
+		t.Fatalf("expected unscoped values to ignore the step, got %q and %q", unscopedStep1Value, unscopedStep2Value)
+	}
+}
+
+func TestExecutePlaceholder_UnscopedEntropyShouldStayUnchanged(t *testing.T) {
+	unscopedInput, err := newGoPlaceholderInput(PlaceholderExecutionRequest{
+		FunctionName:                "Fenix_RandomPositiveDecimalValue",
+		UseEntropyFromExecutionUUID: true,
+		ExtraEntropy:                7,
+		TestCaseExecutionUUID:       "entropy-scope-unchanged",
+		EntropyScopeIDs:             EntropyScopeIDs{SuiteID: "suite-1", StepID: "step-1"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	legacyEntropy := deriveEntropy(EntropySchemeLegacy, entropyParameters{
+		testCaseExecutionUUID:       "entropy-scope-unchanged",
+		useEntropyFromExecutionUUID: true,
+		extraEntropy:                7,
+	})
+	logPlaceholderInputMatrix(t, "unscoped", unscopedInput)
+	if unscopedInput.Entropy != legacyEntropy {
+		t.Fatalf("expected unscoped entropy %d, got %d", legacyEntropy, unscopedInput.Entropy)
+	}
+}
+
+func TestExecutePlaceholder_SuiteScopeShouldBeReproducibleAcrossExecutions(t *testing.T) {
+	var values []string
+	for _, testCaseExecutionUUID := range []string{"entropy-scope-run-1", "entropy-scope-run-2"} {
+		request := scopedDecimalRequest(testCaseExecutionUUID, EntropyScopeSuite)
+		request.UseEntropyFromExecutionUUID = false
+		request.EntropyScopeIDs = EntropyScopeIDs{SuiteID: "regression-suite"}
+
+		logExecutionRequest(t, testCaseExecutionUUID, request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, testCaseExecutionUUID, value, err)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		values = append(values, value)
+	}
+
+	if values[0] != values[1] {
+		t.Fatalf("expected the same suite value in both executions, got %q and %q", values[0], values[1])
+	}
+}
+
+func TestExecutePlaceholder_TemplateScopeShouldUseRequestInstanceID(t *testing.T) {
+	var values []string
+	for _, templateInstanceID := range []string{"template-instance-1", "template-instance-2"} {
+		request := scopedDecimalRequest("entropy-scope-template-execution", EntropyScopeTemplate)
+		request.EntropyScopeIDs = EntropyScopeIDs{TemplateInstanceID: templateInstanceID}
+		request.EntropyScheme = EntropySchemeHMACSHA256V1
+
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, templateInstanceID, value, err)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		values = append(values, value)
+	}
+
+	if values[0] == values[1] {
+		t.Fatalf("expected distinct values per template instance, got %q twice", values[0])
+	}
+}
+
+func TestExecutePlaceholder_ShouldValidateEntropyScope(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request PlaceholderExecutionRequest
+	}{
+		{name: "unknown scope", request: scopedDecimalRequest("entropy-scope-validation", "galaxy")},
+		{name: "missing step ID", request: scopedDecimalRequest("entropy-scope-validation", EntropyScopeStep)},
+		{name: "missing template instance ID", request: scopedDecimalRequest("entropy-scope-validation", EntropyScopeTemplate)},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := ExecutePlaceholder(testCase.request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInvalidInput {
+				t.Fatalf("expected invalid_input error, got: %v", err)
+			}
+		})
+	}
+
+	if err := SetPlaceholderExecutionStep("entropy-scope-not-started", "step-1"); err == nil {
+		t.Fatalf("expected error when setting the step of an execution that isn't started")
+	}
+}
+
+func TestParseLegacyPlaceholderRequest_ShouldAcceptOptionalEntropyScope(t *testing.T) {
+	input := []interface{}{"{{X()}(scope=step)}}", "X", []interface{}{}, []interface{}{}, true, uint64(0), "step"}
+	logDispatcherInputMatrix(t, "legacy-scope", input, "entropy-scope-legacy")
+	request, err := parseLegacyPlaceholderRequest(input, "entropy-scope-legacy")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if request.EntropyScope != EntropyScopeStep {
+		t.Fatalf("expected scope %q, got %q", EntropyScopeStep, request.EntropyScope)
+	}
+
+	input[6] = 3
+	if _, err = parseLegacyPlaceholderRequest(input, "entropy-scope-legacy"); err == nil {
+		t.Fatalf("expected error for non-string entropy scope")
+	}
+}
@@ -0,0 +1,182 @@
+package scriptEngine
+
+import (
+	"testing"
+)
+
+func TestDeriveEntropySeed_LegacySchemeCollidesAndV1DoesNot(t *testing.T) {
+	parametersWithEntropy5 := entropyParameters{functionName: "Fenix_RandomPositiveDecimalValue", extraEntropy: 5}
+	parametersWithEntropy4 := entropyParameters{functionName: "Fenix_RandomPositiveDecimalValue", extraEntropy: 4}
+
+	legacySeedA := deriveEntropySeed(EntropySchemeLegacy, deriveEntropy(EntropySchemeLegacy, parametersWithEntropy5), parametersWithEntropy5, 2)
+	legacySeedB := deriveEntropySeed(EntropySchemeLegacy, deriveEntropy(EntropySchemeLegacy, parametersWithEntropy4), parametersWithEntropy4, 3)
+	v1SeedA := deriveEntropySeed(EntropySchemeHMACSHA256V1, 0, parametersWithEntropy5, 2)
+	v1SeedB := deriveEntropySeed(EntropySchemeHMACSHA256V1, 0, parametersWithEntropy4, 3)
+	t.Logf("Output [seed-collision]\n  Legacy: %d / %d\n  V1: %d / %d", legacySeedA, legacySeedB, v1SeedA, v1SeedB)
+
+	if legacySeedA != legacySeedB {
+		t.Fatalf("expected legacy seeds to keep their original arithmetic, got %d and %d", legacySeedA, legacySeedB)
+	}
+	if v1SeedA == v1SeedB {
+		t.Fatalf("expected v1 seeds to differ for index 2/entropy 5 and index 3/entropy 4")
+	}
+}
+
+func TestDeriveEntropySeed_V1ShouldGiveIndependentStreams(t *testing.T) {
+	baseParameters := entropyParameters{
+		testCaseExecutionUUID:       "entropy-uuid",
+		useEntropyFromExecutionUUID: true,
+		functionName:                "Fenix_RandomPositiveDecimalValue",
+		extraEntropy:                5,
+	}
+
+	// The v1 derivation is versioned, so this value must never change.
+	baseSeed := deriveEntropySeed(EntropySchemeHMACSHA256V1, 0, baseParameters, 2)
+	if baseSeed != 7579281234543917834 {
+		t.Fatalf("v1 seed derivation changed, got %d", baseSeed)
+	}
+
+	otherUUID := baseParameters
+	otherUUID.testCaseExecutionUUID = "other-entropy-uuid"
+	otherFunction := baseParameters
+	otherFunction.functionName = "Fenix_ControlledUniqueId"
+	withoutUUID := baseParameters
+	withoutUUID.useEntropyFromExecutionUUID = false
+
+	testCases := []struct {
+		name       string
+		parameters entropyParameters
+		arrayIndex int
+	}{
+		{name: "other execution UUID", parameters: otherUUID, arrayIndex: 2},
+		{name: "other function name", parameters: otherFunction, arrayIndex: 2},
+		{name: "other array index", parameters: baseParameters, arrayIndex: 3},
+		{name: "UUID entropy disabled", parameters: withoutUUID, arrayIndex: 2},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			seed := deriveEntropySeed(EntropySchemeHMACSHA256V1, 0, testCase.parameters, testCase.arrayIndex)
+			t.Logf("Output [%s]\n  Seed: %d\n  Base seed: %d", testCase.name, seed, baseSeed)
+			if seed == baseSeed {
+				t.Fatalf("expected an independent seed, got the base seed %d", seed)
+			}
+		})
+	}
+}
+
+func TestExecutePlaceholder_ShouldSelectEntropySchemePerCall(t *testing.T) {
+	request := PlaceholderExecutionRequest{
+		FunctionName:                "Fenix_RandomPositiveDecimalValue",
+		ArrayIndexes:                []int{2},
+		Arguments:                   []string{"3", "2", "3", "2", "."},
+		UseEntropyFromExecutionUUID: true,
+		ExtraEntropy:                5,
+		TestCaseExecutionUUID:       "entropy-uuid",
+	}
+
+	defaultValue, err := ExecutePlaceholder(request)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	legacyRequest := request
+	legacyRequest.EntropyScheme = EntropySchemeLegacy
+	legacyValue, _ := ExecutePlaceholder(legacyRequest)
+
+	v1Request := request
+	v1Request.EntropyScheme = EntropySchemeHMACSHA256V1
+	logExecutionRequest(t, "v1-scheme", v1Request)
+	v1Value, err := ExecutePlaceholder(v1Request)
+	logPlaceholderExecutionResult(t, "v1-scheme", v1Value, err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	t.Logf("Output [scheme-per-call]\n  Default: %q\n  Legacy: %q\n  V1: %q", defaultValue, legacyValue, v1Value)
+	if defaultValue != legacyValue {
+		t.Fatalf("expected legacy to stay the default scheme, got %q and %q", defaultValue, legacyValue)
+	}
+	if v1Value != "146.56" {
+		t.Fatalf("expected v1 value %q, got %q", "146.56", v1Value)
+	}
+
+	unknownRequest := request
+	unknownRequest.EntropyScheme = "crc64"
+	_, err = ExecutePlaceholder(unknownRequest)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInvalidInput {
+		t.Fatalf("expected invalid_input error for unknown scheme, got: %v", err)
+	}
+}
+
+func TestExecutePlaceholder_ShouldResolveEntropySchemeFromExecutionAndDefault(t *testing.T) {
+	if err := SetDefaultEntropyScheme("crc64"); err == nil {
+		t.Fatalf("expected error for unknown default scheme")
+	}
+	if err := StartPlaceholderExecution("entropy-invalid-execution", PlaceholderExecutionOptions{EntropyScheme: "crc64"}); err == nil {
+		t.Fatalf("expected error for unknown execution scheme")
+	}
+
+	if err := StartPlaceholderExecution("entropy-v1-execution", PlaceholderExecutionOptions{
+		EntropyScheme: EntropySchemeHMACSHA256V1,
+	}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution("entropy-v1-execution")
+
+	executionInput, err := newGoPlaceholderInput(PlaceholderExecutionRequest{
+		FunctionName:          "Fenix_RandomPositiveDecimalValue",
+		TestCaseExecutionUUID: "entropy-v1-execution",
+	})
+	if err != nil || executionInput.EntropyScheme != EntropySchemeHMACSHA256V1 {
+		t.Fatalf("expected execution scheme %q, got %q (%v)", EntropySchemeHMACSHA256V1, executionInput.EntropyScheme, err)
+	}
+
+	overriddenInput, _ := newGoPlaceholderInput(PlaceholderExecutionRequest{
+		FunctionName:          "Fenix_RandomPositiveDecimalValue",
+		EntropyScheme:         EntropySchemeLegacy,
+		TestCaseExecutionUUID: "entropy-v1-execution",
+	})
+	if overriddenInput.EntropyScheme != EntropySchemeLegacy {
+		t.Fatalf("expected request scheme to override execution scheme, got %q", overriddenInput.EntropyScheme)
+	}
+
+	if err = SetDefaultEntropyScheme(EntropySchemeHMACSHA256V1); err != nil {
+		t.Fatalf("failed to set default scheme: %v", err)
+	}
+	defer SetDefaultEntropyScheme(EntropySchemeLegacy)
+
+	defaultInput, _ := newGoPlaceholderInput(PlaceholderExecutionRequest{FunctionName: "Fenix_RandomPositiveDecimalValue"})
+	t.Logf("Output [scheme-resolution]\n  Execution: %s\n  Request override: %s\n  Default: %s",
+		executionInput.EntropyScheme, overriddenInput.EntropyScheme, defaultInput.EntropyScheme)
+	if defaultInput.EntropyScheme != EntropySchemeHMACSHA256V1 {
+		t.Fatalf("expected default scheme %q, got %q", EntropySchemeHMACSHA256V1, defaultInput.EntropyScheme)
+	}
+}
+
+func TestReplayPlaceholderRecords_ShouldUseRecordedEntropyScheme(t *testing.T) {
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	_, err := ExecutePlaceholder(PlaceholderExecutionRequest{
+		FunctionName:                "Fenix_RandomPositiveDecimalValue",
+		Arguments:                   []string{"3", "2", "3", "2", "."},
+		UseEntropyFromExecutionUUID: true,
+		EntropyScheme:               EntropySchemeHMACSHA256V1,
+		TestCaseExecutionUUID:       "entropy-replay-execution",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	report, err := ReplayPlaceholderExecution("entropy-replay-execution")
+	logPlaceholderReplayReport(t, "entropy-replay", report)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.HasDivergences() == true || report.Results[0].Input.EntropyScheme != EntropySchemeHMACSHA256V1 {
+		t.Fatalf("expected replay with recorded v1 scheme, got %+v", report.Results)
+	}
+}
@@ -0,0 +1,277 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PlaceholderExecutionRequest is the typed input used by ExecutePlaceholder.
+// It carries the same information as the legacy positional []interface{} input.
+type PlaceholderExecutionRequest struct {
+	// Raw placeholder as written in template, for diagnostics and logging.
+	Placeholder string
+	// Canonical runtime function name (dots replaced by underscores).
+	FunctionName string
+	// Optional array indexes from placeholder syntax.
+	ArrayIndexes []int
+	// Positional function arguments as strings.
+	Arguments []string
+	// Controls whether execution UUID contributes to deterministic entropy.
+	UseEntropyFromExecutionUUID bool
+	// User-supplied entropy offset.
+	ExtraEntropy uint64
+	// Optional entropy derivation scheme. Empty means the execution or default scheme.
+	EntropyScheme EntropyScheme
+	// Optional entropy scope, for example EntropyScopeStep. Empty keeps execution UUID entropy only.
+	EntropyScope EntropyScope
+	// Optional scope IDs. Non-empty IDs replace the IDs of the started execution.
+	EntropyScopeIDs EntropyScopeIDs
+	// Execution UUID used for deterministic entropy generation.
+	TestCaseExecutionUUID string
+	// Optional fixed clock instant for date/time handlers. Zero means the execution clock is used.
+	ClockTime time.Time
+	// Optional TestData domain, used for domain specific settings such as time zone.
+	TestDataDomainName string
+	// Optional TestData values of the rendering, per column data name. Lua functions read them with 'fenix.testdata'.
+	TestDataValues map[string]string
+	// Optional field path applied to a structured result, for example ["zip"] for {{Fenix.Address().zip}}.
+	FieldPath []string
+}
+
+// PlaceholderErrorKind classifies why a placeholder execution failed.
+type PlaceholderErrorKind string
+
+const (
+	// PlaceholderErrorKindInvalidInput is used when the request itself is malformed.
+	PlaceholderErrorKindInvalidInput PlaceholderErrorKind = "invalid_input"
+	// PlaceholderErrorKindFunctionNotFound is used when neither Go nor Lua knows the function.
+	PlaceholderErrorKindFunctionNotFound PlaceholderErrorKind = "function_not_found"
+	// PlaceholderErrorKindEngine is used when the Lua engine is not available.
+	PlaceholderErrorKindEngine PlaceholderErrorKind = "engine"
+	// PlaceholderErrorKindHandler is used when a Go handler or Lua function reports an error.
+	PlaceholderErrorKindHandler PlaceholderErrorKind = "handler"
+	// PlaceholderErrorKindLuaRuntime is used when the Lua VM raises an error during the call.
+	PlaceholderErrorKindLuaRuntime PlaceholderErrorKind = "lua_runtime"
+	// PlaceholderErrorKindInvalidResponse is used when a Lua function breaks the response contract.
+	PlaceholderErrorKindInvalidResponse PlaceholderErrorKind = "invalid_response"
+	// PlaceholderErrorKindPanic is used when a handler or interceptor panics.
+	PlaceholderErrorKindPanic PlaceholderErrorKind = "panic"
+	// PlaceholderErrorKindInterceptor is used for errors created by an interceptor itself.
+	PlaceholderErrorKindInterceptor PlaceholderErrorKind = "interceptor"
+	// PlaceholderErrorKindFieldAccess is used when the field path does not exist in the result.
+	PlaceholderErrorKindFieldAccess PlaceholderErrorKind = "field_access"
+	// PlaceholderErrorKindResourceLimit is used when a Lua function exceeds its instruction budget.
+	PlaceholderErrorKindResourceLimit PlaceholderErrorKind = "resource_limit"
+)
+
+// PlaceholderExecutionError is returned by ExecutePlaceholder for all failures.
+// The message is kept identical to the wrapped error so legacy string output does not change.
+type PlaceholderExecutionError struct {
+	Kind         PlaceholderErrorKind
+	FunctionName string
+	Err          error
+}
+
+func (placeholderExecutionError *PlaceholderExecutionError) Error() string {
+	return placeholderExecutionError.Err.Error()
+}
+
+func (placeholderExecutionError *PlaceholderExecutionError) Unwrap() error {
+	return placeholderExecutionError.Err
+}
+
+// newPlaceholderExecutionError wraps err with kind unless it already is a PlaceholderExecutionError.
+func newPlaceholderExecutionError(kind PlaceholderErrorKind, functionName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*PlaceholderExecutionError); ok == true {
+		return err
+	}
+
+	return &PlaceholderExecutionError{
+		Kind:         kind,
+		FunctionName: functionName,
+		Err:          err,
+	}
+}
+
+// PlaceholderErrorKindOf returns the kind of a placeholder execution error, or "" for other errors.
+func PlaceholderErrorKindOf(err error) PlaceholderErrorKind {
+	if placeholderExecutionError, ok := err.(*PlaceholderExecutionError); ok == true {
+		return placeholderExecutionError.Kind
+	}
+
+	return ""
+}
+
+// ExecutePlaceholder executes one placeholder function from a typed request and returns the formatted value.
+// The dispatch policy selects Go handler or Lua function, by default Go first with Lua as fallback.
+func ExecutePlaceholder(request PlaceholderExecutionRequest) (value string, err error) {
+	placeholderValue, err := ExecutePlaceholderValue(request)
+	if err != nil {
+		return "", err
+	}
+
+	return placeholderValue.Format(), nil
+}
+
+// ExecutePlaceholderValue executes one placeholder function from a typed request and returns the typed value.
+func ExecutePlaceholderValue(request PlaceholderExecutionRequest) (value PlaceholderValue, err error) {
+	result, err := ExecutePlaceholderWithResult(request)
+
+	return result.Value, err
+}
+
+// PlaceholderExecutionResult is the typed value of a call together with debugging output.
+type PlaceholderExecutionResult struct {
+	Value PlaceholderValue
+	// Lines printed or logged by Lua during the call, in order. Also set when the call fails.
+	LuaOutput []LuaOutputLine
+}
+
+// ExecutePlaceholderWithResult executes one placeholder function from a typed request and returns the typed value
+// with the Lua output of the call.
+func ExecutePlaceholderWithResult(request PlaceholderExecutionRequest) (result PlaceholderExecutionResult, err error) {
+	input, err := newGoPlaceholderInput(request)
+	if err != nil {
+		return result, err
+	}
+
+	if replaySession := lookupPlaceholderReplaySession(input.TestCaseExecutionUUID); replaySession != nil {
+		result.Value, err = replaySession.execute(input)
+	} else {
+		result.Value, err = executePlaceholderInput(input)
+	}
+	result.LuaOutput = input.luaOutput.snapshot()
+
+	return result, err
+}
+
+// newGoPlaceholderInput validates a typed request and derives the values used by handlers.
+func newGoPlaceholderInput(request PlaceholderExecutionRequest) (goInput GoPlaceholderInput, err error) {
+	functionName := strings.TrimSpace(request.FunctionName)
+	if functionName == "" {
+		return goInput, newPlaceholderExecutionError(
+			PlaceholderErrorKindInvalidInput, "", fmt.Errorf("function name can not be empty"))
+	}
+
+	executionContext := lookupPlaceholderExecutionContext(request.TestCaseExecutionUUID)
+	entropyScheme, err := resolveEntropyScheme(executionContext, request.EntropyScheme)
+	if err != nil {
+		return goInput, newPlaceholderExecutionError(PlaceholderErrorKindInvalidInput, functionName, err)
+	}
+	entropyScopeIDs := resolveEntropyScopeIDs(executionContext, request.TestCaseExecutionUUID, request.EntropyScopeIDs)
+	if err = validateEntropyScope(request.EntropyScope, entropyScopeIDs); err != nil {
+		return goInput, newPlaceholderExecutionError(PlaceholderErrorKindInvalidInput, functionName, err)
+	}
+
+	goInput = GoPlaceholderInput{
+		Placeholder:                 request.Placeholder,
+		FunctionName:                functionName,
+		ArrayIndexes:                append([]int{}, request.ArrayIndexes...),
+		Arguments:                   normalizeArguments(request.Arguments),
+		UseEntropyFromExecutionUUID: request.UseEntropyFromExecutionUUID,
+		ExtraEntropy:                request.ExtraEntropy,
+		EntropyScheme:               entropyScheme,
+		EntropyScope:                request.EntropyScope,
+		EntropyScopeIDs:             entropyScopeIDs,
+		TestCaseExecutionUUID:       request.TestCaseExecutionUUID,
+		ClockTime:                   request.ClockTime,
+		FieldPath:                   append([]string{}, request.FieldPath...),
+		TestDataValues:              request.TestDataValues,
+		luaOutput:                   &luaOutputCapture{},
+	}
+	goInput.Entropy = deriveEntropy(entropyScheme, goInput.entropyParameters())
+
+	if executionContext != nil {
+		goInput.executionStore = executionContext.store
+	}
+
+	goInput.TestDataDomainName = resolveTestDataDomainName(executionContext, request.TestDataDomainName)
+	if goInput.ClockTime.IsZero() == true {
+		goInput.ClockTime = resolveExecutionClockTime(executionContext, goInput.TestDataDomainName)
+	}
+
+	return goInput, nil
+}
+
+// executePlaceholderInput routes parsed input to a Go handler or, when none exists, to Lua.
+// Every call is added to the audit log.
+func executePlaceholderInput(input GoPlaceholderInput) (value PlaceholderValue, err error) {
+	value, runtime, err := dispatchPlaceholderInput(input)
+	recordPlaceholderAudit(input, runtime, value, err)
+	if err != nil {
+		scriptEngineLogger().Warn("placeholder execution failed", logKeyFunction, input.FunctionName,
+			logKeyExecutionUUID, input.TestCaseExecutionUUID, "runtime", runtime, "errorKind", PlaceholderErrorKindOf(err), "error", err)
+	}
+
+	return value, err
+}
+
+// dispatchPlaceholderInput runs the interceptor chain around the selected runtime, applies the field path and records metrics.
+func dispatchPlaceholderInput(input GoPlaceholderInput) (value PlaceholderValue, runtime PlaceholderRuntime, err error) {
+	runtime, handler := resolvePlaceholderHandler(input)
+
+	startTime := time.Now()
+	value, err = invokePlaceholderInterceptors(PlaceholderCall{Input: input, Runtime: runtime}, handler)
+	if err == nil && len(input.FieldPath) > 0 {
+		value, err = value.Field(input.FieldPath...)
+		err = newPlaceholderExecutionError(PlaceholderErrorKindFieldAccess, input.FunctionName, err)
+	}
+	recordPlaceholderMetrics(input.FunctionName, runtime, time.Since(startTime), err)
+
+	return value, runtime, err
+}
+
+// resolvePlaceholderHandler selects the runtime and handler used for a call from the dispatch policy.
+// The handler validates the call against the metadata of the selected runtime; Lua functions validate their calls
+// in executeLuaPlaceholderFunction.
+func resolvePlaceholderHandler(input GoPlaceholderInput) (runtime PlaceholderRuntime, handler PlaceholderHandler) {
+	decision := ResolvePlaceholderDispatch(input.FunctionName, input.TestDataDomainName)
+	logPlaceholderDispatchDecision(decision, input.TestCaseExecutionUUID)
+
+	if decision.Runtime == PlaceholderRuntimeLua {
+		return PlaceholderRuntimeLua, executeLuaPlaceholderFunction
+	}
+
+	goFunction, exists := lookupGoPlaceholderFunction(input.FunctionName)
+	if exists == false {
+		return PlaceholderRuntimeGo, func(input GoPlaceholderInput) (PlaceholderValue, error) {
+			return PlaceholderValue{}, newPlaceholderExecutionError(
+				PlaceholderErrorKindFunctionNotFound,
+				input.FunctionName,
+				fmt.Errorf("no Go placeholder function registered for '%s' and dispatch policy is '%s'", input.FunctionName, decision.Policy))
+		}
+	}
+
+	handler = func(input GoPlaceholderInput) (PlaceholderValue, error) {
+		return callGoPlaceholderFunction(goFunction, input)
+	}
+	if isPlaceholderShadowed(input.FunctionName) == true {
+		handler = shadowPlaceholderHandler(handler)
+	}
+
+	return PlaceholderRuntimeGo, validatedPlaceholderHandler(PlaceholderRuntimeGo, handler)
+}
+
+// callGoPlaceholderFunction calls a Go handler and converts errors and panics into PlaceholderExecutionError.
+func callGoPlaceholderFunction(goFunction GoPlaceholderValueFunction, input GoPlaceholderInput) (value PlaceholderValue, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			value = PlaceholderValue{}
+			err = newPlaceholderExecutionError(
+				PlaceholderErrorKindPanic,
+				input.FunctionName,
+				fmt.Errorf("go placeholder function '%s' panicked: %v", input.FunctionName, recovered))
+		}
+	}()
+
+	value, err = goFunction(input)
+	if err != nil {
+		return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindHandler, input.FunctionName, err)
+	}
+
+	return value, nil
+}
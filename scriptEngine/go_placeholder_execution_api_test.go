@@ -0,0 +1,189 @@
+package scriptEngine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func logExecutionRequest(t *testing.T, callLabel string, request PlaceholderExecutionRequest) {
+	t.Helper()
+	t.Logf(
+		"Execution request [%s]\n  Placeholder: %q\n  FunctionName: %q\n  ArrayIndexes: %v\n  Arguments: %v\n  UseEntropyFromExecutionUUID: %t\n  ExtraEntropy: %d\n  TestCaseExecutionUUID: %q",
+		callLabel,
+		request.Placeholder,
+		request.FunctionName,
+		request.ArrayIndexes,
+		request.Arguments,
+		request.UseEntropyFromExecutionUUID,
+		request.ExtraEntropy,
+		request.TestCaseExecutionUUID,
+	)
+}
+
+func TestExecutePlaceholder_ShouldExecuteGoHandlerFromTypedRequest(t *testing.T) {
+	originalTimeProvider := currentTimeProvider
+	currentTimeProvider = func() time.Time {
+		return time.Date(2026, time.February, 26, 12, 30, 45, 0, time.Local)
+	}
+	defer func() {
+		currentTimeProvider = originalTimeProvider
+	}()
+
+	request := PlaceholderExecutionRequest{
+		Placeholder:           "{{Fenix.TodayShiftDay(1)}}",
+		FunctionName:          "Fenix_TodayShiftDay",
+		Arguments:             []string{" 1 "},
+		TestCaseExecutionUUID: "execution-uuid",
+	}
+
+	logExecutionRequest(t, "typed-go-handler", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "typed-go-handler", value, err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if value != "2026-02-27" {
+		t.Fatalf("expected 2026-02-27, got %s", value)
+	}
+}
+
+func TestExecutePlaceholder_ShouldClassifyErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		request      PlaceholderExecutionRequest
+		expectedKind PlaceholderErrorKind
+	}{
+		{
+			name:         "empty function name",
+			request:      PlaceholderExecutionRequest{FunctionName: "  "},
+			expectedKind: PlaceholderErrorKindInvalidInput,
+		},
+		{
+			name: "handler validation error",
+			request: PlaceholderExecutionRequest{
+				FunctionName: "Fenix_TodayShiftDay",
+				Arguments:    []string{"notAnInt"},
+			},
+			expectedKind: PlaceholderErrorKindHandler,
+		},
+		{
+			name:         "lua engine not initiated",
+			request:      PlaceholderExecutionRequest{FunctionName: "Fenix_Unknown"},
+			expectedKind: PlaceholderErrorKindEngine,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			logExecutionRequest(t, testCase.name, testCase.request)
+			value, err := ExecutePlaceholder(testCase.request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if PlaceholderErrorKindOf(err) != testCase.expectedKind {
+				t.Fatalf("expected error kind %q, got %q (%v)", testCase.expectedKind, PlaceholderErrorKindOf(err), err)
+			}
+		})
+	}
+}
+
+func TestExecutePlaceholder_ShouldRecoverFromPanickingGoHandler(t *testing.T) {
+	const functionName = "Test_PanickingPlaceholder"
+	if err := RegisterGoPlaceholderFunction(functionName, func(input GoPlaceholderInput) (string, error) {
+		panic("boom")
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	defer func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, functionName)
+		goPlaceholderFunctionsMutex.Unlock()
+	}()
+
+	request := PlaceholderExecutionRequest{FunctionName: functionName}
+	logExecutionRequest(t, "panicking-handler", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "panicking-handler", value, err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindPanic {
+		t.Fatalf("expected panic error kind, got %q (%v)", PlaceholderErrorKindOf(err), err)
+	}
+}
+
+func TestExecuteLuaScriptBasedOnPlaceholder_ShouldNeverPanicOnMalformedInput(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         []interface{}
+		expectedError string
+	}{
+		{name: "nil input", input: nil, expectedError: "expected at least 6 input parameters"},
+		{name: "short input", input: []interface{}{"{{X()}}", "X"}, expectedError: "expected at least 6 input parameters"},
+		{
+			name:          "function name not a string",
+			input:         []interface{}{"{{X()}}", 42, []interface{}{}, []interface{}{}, true, uint64(0)},
+			expectedError: "input parameter 1 ('functionName') must be a string",
+		},
+		{
+			name:          "array indexes not a slice",
+			input:         []interface{}{"{{X()}}", "X", nil, []interface{}{}, true, uint64(0)},
+			expectedError: "input parameter 2 ('arrayIndexes') must be []interface{}",
+		},
+		{
+			name:          "use entropy not a bool",
+			input:         []interface{}{"{{X()}}", "X", []interface{}{}, []interface{}{}, "true", uint64(0)},
+			expectedError: "input parameter 4 ('useEntropyFromExecutionUuid') must be bool",
+		},
+		{
+			name:          "extra entropy not uint64",
+			input:         []interface{}{"{{X()}}", "X", []interface{}{}, []interface{}{}, true, 0},
+			expectedError: "input parameter 5 ('extraEntropy') must be uint64",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			logDispatcherInputMatrix(t, testCase.name, testCase.input, "execution-uuid")
+			response := ExecuteLuaScriptBasedOnPlaceholder(testCase.input, "execution-uuid")
+			t.Logf("Output [%s]\n  Response: %q", testCase.name, response)
+			if strings.Contains(response, testCase.expectedError) == false {
+				t.Fatalf("expected response to contain %q, got %q", testCase.expectedError, response)
+			}
+		})
+	}
+}
+
+func TestExecuteLuaScriptBasedOnPlaceholder_ShouldMatchTypedApi(t *testing.T) {
+	input := []interface{}{
+		"{{Fenix.RandomPositiveDecimalValue[2](2, 3, 2, 3, \".\")}}",
+		"Fenix_RandomPositiveDecimalValue",
+		[]interface{}{2},
+		[]interface{}{"2", "3", "2", "3", "."},
+		true,
+		uint64(0),
+	}
+	testCaseExecutionUUID := "f8c06f7e-0a8a-4d75-9f25-5e5fb8d2a6d3"
+
+	logDispatcherInputMatrix(t, "legacy-adapter", input, testCaseExecutionUUID)
+	legacyResponse := ExecuteLuaScriptBasedOnPlaceholder(input, testCaseExecutionUUID)
+
+	request := PlaceholderExecutionRequest{
+		Placeholder:                 "{{Fenix.RandomPositiveDecimalValue[2](2, 3, 2, 3, \".\")}}",
+		FunctionName:                "Fenix_RandomPositiveDecimalValue",
+		ArrayIndexes:                []int{2},
+		Arguments:                   []string{"2", "3", "2", "3", "."},
+		UseEntropyFromExecutionUUID: true,
+		TestCaseExecutionUUID:       testCaseExecutionUUID,
+	}
+	logExecutionRequest(t, "typed-api", request)
+	typedResponse, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "typed-api", typedResponse, err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if legacyResponse != typedResponse {
+		t.Fatalf("expected legacy adapter and typed API to agree, got %q and %q", legacyResponse, typedResponse)
+	}
+}
@@ -0,0 +1,255 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecutionClock supplies the current instant for one execution.
+type ExecutionClock interface {
+	Now() time.Time
+}
+
+// fixedExecutionClock always returns the same instant.
+type fixedExecutionClock struct {
+	instant time.Time
+}
+
+// NewFixedExecutionClock returns a clock that is frozen at instant.
+func NewFixedExecutionClock(instant time.Time) ExecutionClock {
+	return fixedExecutionClock{instant: instant}
+}
+
+func (clock fixedExecutionClock) Now() time.Time {
+	return clock.instant
+}
+
+// offsetExecutionClock follows the engine clock with a constant offset.
+type offsetExecutionClock struct {
+	offset time.Duration
+}
+
+// NewOffsetExecutionClock returns a clock that runs at real speed but shifted by offset.
+func NewOffsetExecutionClock(offset time.Duration) ExecutionClock {
+	return offsetExecutionClock{offset: offset}
+}
+
+func (clock offsetExecutionClock) Now() time.Time {
+	return currentTimeProvider().Add(clock.offset)
+}
+
+// ManualExecutionClock only moves when it is advanced or set.
+type ManualExecutionClock struct {
+	mutex   sync.Mutex
+	instant time.Time
+}
+
+// NewManualExecutionClock returns a clock that starts at start and is moved manually.
+func NewManualExecutionClock(start time.Time) *ManualExecutionClock {
+	return &ManualExecutionClock{instant: start}
+}
+
+func (clock *ManualExecutionClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.instant
+}
+
+// Advance moves the clock forward by duration, or backwards for negative durations.
+func (clock *ManualExecutionClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	clock.instant = clock.instant.Add(duration)
+	clock.mutex.Unlock()
+}
+
+// Set moves the clock to instant.
+func (clock *ManualExecutionClock) Set(instant time.Time) {
+	clock.mutex.Lock()
+	clock.instant = instant
+	clock.mutex.Unlock()
+}
+
+// PlaceholderExecutionOptions configures one execution started with StartPlaceholderExecution.
+type PlaceholderExecutionOptions struct {
+	// Clock used by date/time handlers. Nil means the engine clock.
+	Clock ExecutionClock
+	// IANA time zone, for example "Europe/Stockholm". Empty means TestData domain time zone or host local time.
+	TimeZone string
+	// TestData domain used when a request doesn't name its own domain.
+	TestDataDomainName string
+	// Entropy scheme used when a request doesn't select its own. Empty means the default scheme.
+	EntropyScheme EntropyScheme
+	// Suite, test case and step IDs used by entropy scopes. The test case ID defaults to the execution UUID.
+	// The step ID can be changed while the execution runs with SetPlaceholderExecutionStep.
+	EntropyScopeIDs EntropyScopeIDs
+}
+
+// placeholderExecutionContext holds the per-execution settings.
+type placeholderExecutionContext struct {
+	clock              ExecutionClock
+	location           *time.Location
+	testDataDomainName string
+	entropyScheme      EntropyScheme
+
+	entropyScopeIDsMutex sync.RWMutex
+	entropyScopeIDs      EntropyScopeIDs
+
+	// Key/value store shared by the placeholder calls of the execution.
+	store *PlaceholderExecutionStore
+}
+
+var (
+	placeholderExecutionContextsMutex sync.RWMutex
+	// Started executions per execution UUID.
+	placeholderExecutionContexts = map[string]*placeholderExecutionContext{}
+
+	testDataDomainLocationsMutex sync.RWMutex
+	// Time zones configured per TestData domain name.
+	testDataDomainLocations = map[string]*time.Location{}
+)
+
+// StartPlaceholderExecution registers per-execution settings for an execution UUID.
+// Executions that are never started use the engine clock and host local time.
+func StartPlaceholderExecution(testCaseExecutionUUID string, options PlaceholderExecutionOptions) error {
+	if strings.TrimSpace(testCaseExecutionUUID) == "" {
+		return fmt.Errorf("execution UUID can not be empty")
+	}
+
+	executionContext := &placeholderExecutionContext{
+		clock:              options.Clock,
+		testDataDomainName: options.TestDataDomainName,
+		entropyScheme:      options.EntropyScheme,
+		entropyScopeIDs:    options.EntropyScopeIDs,
+		store:              newPlaceholderExecutionStore(testCaseExecutionUUID),
+	}
+
+	if options.EntropyScheme != "" {
+		if err := validateEntropyScheme(options.EntropyScheme); err != nil {
+			return fmt.Errorf("invalid entropy scheme for execution '%s': %w", testCaseExecutionUUID, err)
+		}
+	}
+
+	if strings.TrimSpace(options.TimeZone) != "" {
+		location, err := time.LoadLocation(strings.TrimSpace(options.TimeZone))
+		if err != nil {
+			return fmt.Errorf("invalid time zone '%s' for execution '%s': %w", options.TimeZone, testCaseExecutionUUID, err)
+		}
+		executionContext.location = location
+	}
+
+	placeholderExecutionContextsMutex.Lock()
+	defer placeholderExecutionContextsMutex.Unlock()
+
+	if _, exists := placeholderExecutionContexts[testCaseExecutionUUID]; exists == true {
+		return fmt.Errorf("execution '%s' is already started", testCaseExecutionUUID)
+	}
+	placeholderExecutionContexts[testCaseExecutionUUID] = executionContext
+
+	return nil
+}
+
+// EndPlaceholderExecution removes the per-execution settings for an execution UUID and discards its store.
+func EndPlaceholderExecution(testCaseExecutionUUID string) {
+	placeholderExecutionContextsMutex.Lock()
+	executionContext := placeholderExecutionContexts[testCaseExecutionUUID]
+	delete(placeholderExecutionContexts, testCaseExecutionUUID)
+	placeholderExecutionContextsMutex.Unlock()
+
+	if executionContext != nil {
+		executionContext.store.discard()
+	}
+}
+
+// SetPlaceholderExecutionStep sets the step ID used by the step entropy scope of a started execution.
+func SetPlaceholderExecutionStep(testCaseExecutionUUID string, stepID string) error {
+	executionContext := lookupPlaceholderExecutionContext(testCaseExecutionUUID)
+	if executionContext == nil {
+		return fmt.Errorf("execution '%s' is not started", testCaseExecutionUUID)
+	}
+
+	executionContext.entropyScopeIDsMutex.Lock()
+	executionContext.entropyScopeIDs.StepID = stepID
+	executionContext.entropyScopeIDsMutex.Unlock()
+
+	return nil
+}
+
+// currentEntropyScopeIDs returns the scope IDs of the execution.
+func (executionContext *placeholderExecutionContext) currentEntropyScopeIDs() EntropyScopeIDs {
+	executionContext.entropyScopeIDsMutex.RLock()
+	defer executionContext.entropyScopeIDsMutex.RUnlock()
+
+	return executionContext.entropyScopeIDs
+}
+
+// SetTestDataDomainTimeZone sets the IANA time zone for a TestData domain. An empty time zone removes it.
+func SetTestDataDomainTimeZone(testDataDomainName string, timeZone string) error {
+	if strings.TrimSpace(testDataDomainName) == "" {
+		return fmt.Errorf("TestData domain name can not be empty")
+	}
+
+	testDataDomainLocationsMutex.Lock()
+	defer testDataDomainLocationsMutex.Unlock()
+
+	if strings.TrimSpace(timeZone) == "" {
+		delete(testDataDomainLocations, testDataDomainName)
+		return nil
+	}
+
+	location, err := time.LoadLocation(strings.TrimSpace(timeZone))
+	if err != nil {
+		return fmt.Errorf("invalid time zone '%s' for TestData domain '%s': %w", timeZone, testDataDomainName, err)
+	}
+	testDataDomainLocations[testDataDomainName] = location
+
+	return nil
+}
+
+// lookupPlaceholderExecutionContext returns the started execution for a UUID, or nil.
+func lookupPlaceholderExecutionContext(testCaseExecutionUUID string) *placeholderExecutionContext {
+	placeholderExecutionContextsMutex.RLock()
+	defer placeholderExecutionContextsMutex.RUnlock()
+
+	return placeholderExecutionContexts[testCaseExecutionUUID]
+}
+
+// resolveTestDataDomainName returns the request domain, or the execution domain when the request has none.
+func resolveTestDataDomainName(executionContext *placeholderExecutionContext, requestTestDataDomainName string) string {
+	if requestTestDataDomainName != "" || executionContext == nil {
+		return requestTestDataDomainName
+	}
+
+	return executionContext.testDataDomainName
+}
+
+// resolveExecutionClockTime returns the current instant for an execution in its configured time zone.
+// Time zone precedence: execution, TestData domain, host local time.
+func resolveExecutionClockTime(executionContext *placeholderExecutionContext, testDataDomainName string) time.Time {
+	now := currentTimeProvider()
+	if executionContext != nil && executionContext.clock != nil {
+		now = executionContext.clock.Now()
+	}
+
+	return now.In(resolveExecutionLocation(executionContext, testDataDomainName))
+}
+
+// resolveExecutionLocation returns the time zone for an execution and TestData domain.
+func resolveExecutionLocation(executionContext *placeholderExecutionContext, testDataDomainName string) *time.Location {
+	if executionContext != nil && executionContext.location != nil {
+		return executionContext.location
+	}
+
+	if testDataDomainName != "" {
+		testDataDomainLocationsMutex.RLock()
+		location, exists := testDataDomainLocations[testDataDomainName]
+		testDataDomainLocationsMutex.RUnlock()
+		if exists == true {
+			return location
+		}
+	}
+
+	return time.Local
+}
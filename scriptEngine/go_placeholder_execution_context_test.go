@@ -0,0 +1,172 @@
+package scriptEngine
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPlaceholderExecution_ShouldUseIndependentClocksForParallelExecutions(t *testing.T) {
+	executions := map[string]time.Time{
+		"clock-execution-a": time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC),
+		"clock-execution-b": time.Date(2030, time.December, 24, 10, 0, 0, 0, time.UTC),
+	}
+	for testCaseExecutionUUID, instant := range executions {
+		if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{
+			Clock:    NewFixedExecutionClock(instant),
+			TimeZone: "UTC",
+		}); err != nil {
+			t.Fatalf("failed to start execution: %v", err)
+		}
+		defer EndPlaceholderExecution(testCaseExecutionUUID)
+	}
+
+	var waitGroup sync.WaitGroup
+	results := sync.Map{}
+	for testCaseExecutionUUID := range executions {
+		for callIndex := 0; callIndex < 20; callIndex++ {
+			waitGroup.Add(1)
+			go func(testCaseExecutionUUID string) {
+				defer waitGroup.Done()
+				value, err := ExecutePlaceholder(PlaceholderExecutionRequest{
+					FunctionName:          "Fenix_TodayShiftDay",
+					Arguments:             []string{"0"},
+					TestCaseExecutionUUID: testCaseExecutionUUID,
+				})
+				if err != nil {
+					value = err.Error()
+				}
+				if previousValue, loaded := results.LoadOrStore(testCaseExecutionUUID, value); loaded == true && previousValue != value {
+					results.Store(testCaseExecutionUUID, "inconsistent")
+				}
+			}(testCaseExecutionUUID)
+		}
+	}
+	waitGroup.Wait()
+
+	valueA, _ := results.Load("clock-execution-a")
+	valueB, _ := results.Load("clock-execution-b")
+	t.Logf("Output [parallel-clocks]\n  Execution A: %v\n  Execution B: %v", valueA, valueB)
+	if valueA != "2026-03-01" || valueB != "2030-12-24" {
+		t.Fatalf("expected independent clocks per execution, got %v and %v", valueA, valueB)
+	}
+}
+
+func TestPlaceholderExecution_ShouldHonorTimeZonePrecedence(t *testing.T) {
+	// 23:30 UTC is already the next day in Stockholm and still the same day in New York.
+	instant := time.Date(2026, time.June, 15, 23, 30, 0, 0, time.UTC)
+
+	if err := SetTestDataDomainTimeZone("Test_Domain_Stockholm", "Europe/Stockholm"); err != nil {
+		t.Fatalf("failed to set domain time zone: %v", err)
+	}
+	defer SetTestDataDomainTimeZone("Test_Domain_Stockholm", "")
+
+	if err := StartPlaceholderExecution("tz-domain-execution", PlaceholderExecutionOptions{
+		Clock:              NewFixedExecutionClock(instant),
+		TestDataDomainName: "Test_Domain_Stockholm",
+	}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution("tz-domain-execution")
+
+	if err := StartPlaceholderExecution("tz-execution-override", PlaceholderExecutionOptions{
+		Clock:              NewFixedExecutionClock(instant),
+		TimeZone:           "America/New_York",
+		TestDataDomainName: "Test_Domain_Stockholm",
+	}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution("tz-execution-override")
+
+	testCases := []struct {
+		name     string
+		request  PlaceholderExecutionRequest
+		expected string
+	}{
+		{
+			name: "domain time zone from execution",
+			request: PlaceholderExecutionRequest{
+				FunctionName:          "Fenix_ControlledUniqueId",
+				Arguments:             []string{"%YYYY-MM-DD% %hh:mm:ss%", "false", "0"},
+				TestCaseExecutionUUID: "tz-domain-execution",
+			},
+			expected: "2026-06-16 01:30:00",
+		},
+		{
+			name: "execution time zone overrides domain",
+			request: PlaceholderExecutionRequest{
+				FunctionName:          "Fenix_ControlledUniqueId",
+				Arguments:             []string{"%YYYY-MM-DD% %hh:mm:ss%", "false", "0"},
+				TestCaseExecutionUUID: "tz-execution-override",
+			},
+			expected: "2026-06-15 19:30:00",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			logExecutionRequest(t, testCase.name, testCase.request)
+			value, err := ExecutePlaceholder(testCase.request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if value != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestPlaceholderExecution_ShouldSupportOffsetAndManualClocks(t *testing.T) {
+	restoreTimeProvider := setTestTimeProvider(time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC))
+	defer restoreTimeProvider()
+
+	offsetClock := NewOffsetExecutionClock(-48 * time.Hour)
+	if offsetClock.Now().Equal(time.Date(2026, time.January, 8, 12, 0, 0, 0, time.UTC)) == false {
+		t.Fatalf("unexpected offset clock instant: %s", offsetClock.Now())
+	}
+
+	manualClock := NewManualExecutionClock(time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC))
+	if err := StartPlaceholderExecution("manual-clock-execution", PlaceholderExecutionOptions{
+		Clock:    manualClock,
+		TimeZone: "UTC",
+	}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution("manual-clock-execution")
+
+	request := PlaceholderExecutionRequest{
+		FunctionName:          "Fenix_TodayShiftDay",
+		Arguments:             []string{"0"},
+		TestCaseExecutionUUID: "manual-clock-execution",
+	}
+	before, _ := ExecutePlaceholder(request)
+	manualClock.Advance(24 * time.Hour)
+	after, _ := ExecutePlaceholder(request)
+	t.Logf("Output [manual-clock]\n  Before: %q\n  After: %q", before, after)
+	if before != "2026-01-31" || after != "2026-02-01" {
+		t.Fatalf("expected manual clock to move from 2026-01-31 to 2026-02-01, got %s and %s", before, after)
+	}
+}
+
+func TestStartPlaceholderExecution_ShouldValidateOptions(t *testing.T) {
+	if err := StartPlaceholderExecution("", PlaceholderExecutionOptions{}); err == nil {
+		t.Fatalf("expected error for empty execution UUID")
+	}
+	if err := StartPlaceholderExecution("invalid-tz-execution", PlaceholderExecutionOptions{TimeZone: "Mars/Olympus"}); err == nil {
+		t.Fatalf("expected error for invalid time zone")
+	}
+	if err := SetTestDataDomainTimeZone("Test_Domain", "Not/AZone"); err == nil {
+		t.Fatalf("expected error for invalid domain time zone")
+	}
+
+	if err := StartPlaceholderExecution("duplicate-execution", PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution("duplicate-execution")
+	if err := StartPlaceholderExecution("duplicate-execution", PlaceholderExecutionOptions{}); err == nil {
+		t.Fatalf("expected error when starting the same execution twice")
+	}
+}
@@ -0,0 +1,187 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlaceholderExecutionStore is a key/value store of one started execution, shared by all its placeholder calls,
+// for example for a running invoice number or a reserved customer. Lua functions use it through fenix.store().
+// It is created by StartPlaceholderExecution and discarded by EndPlaceholderExecution.
+type PlaceholderExecutionStore struct {
+	testCaseExecutionUUID string
+
+	mutex  sync.Mutex
+	values map[string]PlaceholderValue
+	ended  bool
+}
+
+// newPlaceholderExecutionStore returns the empty store of an execution.
+func newPlaceholderExecutionStore(testCaseExecutionUUID string) *PlaceholderExecutionStore {
+	return &PlaceholderExecutionStore{testCaseExecutionUUID: testCaseExecutionUUID, values: map[string]PlaceholderValue{}}
+}
+
+// GetPlaceholderExecutionStore returns the store of a started execution, for example to seed it before rendering.
+func GetPlaceholderExecutionStore(testCaseExecutionUUID string) (*PlaceholderExecutionStore, error) {
+	executionContext := lookupPlaceholderExecutionContext(testCaseExecutionUUID)
+	if executionContext == nil {
+		return nil, fmt.Errorf("execution '%s' is not started", testCaseExecutionUUID)
+	}
+
+	return executionContext.store, nil
+}
+
+// ExecutionStore returns the store of the execution the call belongs to, nil when the execution is not started.
+func (input GoPlaceholderInput) ExecutionStore() *PlaceholderExecutionStore {
+	return input.executionStore
+}
+
+// Get returns the value of a key, and false when the key has no value.
+func (store *PlaceholderExecutionStore) Get(key string) (PlaceholderValue, bool) {
+	if store == nil {
+		return PlaceholderValue{}, false
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	value, exists := store.values[key]
+	if exists == false {
+		return PlaceholderValue{}, false
+	}
+
+	return clonePlaceholderValue(value), true
+}
+
+// Set stores the value of a key.
+func (store *PlaceholderExecutionStore) Set(key string, value PlaceholderValue) error {
+	return store.update(key, func(values map[string]PlaceholderValue) error {
+		values[key] = clonePlaceholderValue(value)
+		return nil
+	})
+}
+
+// SetIfAbsent stores the value only when the key has no value, and returns true when it was stored.
+// Concurrent calls can use it to reserve a value, only one of them gets true.
+func (store *PlaceholderExecutionStore) SetIfAbsent(key string, value PlaceholderValue) (stored bool, err error) {
+	err = store.update(key, func(values map[string]PlaceholderValue) error {
+		if _, exists := values[key]; exists == true {
+			return nil
+		}
+		values[key] = clonePlaceholderValue(value)
+		stored = true
+		return nil
+	})
+
+	return stored, err
+}
+
+// Increment adds delta to the number of a key and returns the new number. A key without value starts at 0.
+func (store *PlaceholderExecutionStore) Increment(key string, delta float64) (number float64, err error) {
+	err = store.update(key, func(values map[string]PlaceholderValue) error {
+		value, exists := values[key]
+		if exists == true && value.kind() != PlaceholderValueKindNumber {
+			return fmt.Errorf("can't increment key '%s' with a %s value", key, value.kind())
+		}
+		number = value.Number + delta
+		values[key] = NumberValue(number)
+		return nil
+	})
+
+	return number, err
+}
+
+// Delete removes the value of a key.
+func (store *PlaceholderExecutionStore) Delete(key string) error {
+	return store.update(key, func(values map[string]PlaceholderValue) error {
+		delete(values, key)
+		return nil
+	})
+}
+
+// Keys returns the keys with a value, sorted.
+func (store *PlaceholderExecutionStore) Keys() []string {
+	if store == nil {
+		return nil
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	keys := make([]string, 0, len(store.values))
+	for key := range store.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// update runs a change of one key while the store is locked. Changes fail for an empty key, without a started
+// execution and after the execution ended.
+func (store *PlaceholderExecutionStore) update(key string, change func(values map[string]PlaceholderValue) error) error {
+	if store == nil {
+		return fmt.Errorf("the execution store is only available in an execution started with StartPlaceholderExecution")
+	}
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("execution store key can not be empty")
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.ended == true {
+		return fmt.Errorf("execution '%s' has ended, its store is discarded", store.testCaseExecutionUUID)
+	}
+
+	return change(store.values)
+}
+
+// discard removes all values when the execution ends. Handlers that kept the store can no longer change it.
+func (store *PlaceholderExecutionStore) discard() {
+	store.mutex.Lock()
+	store.values = map[string]PlaceholderValue{}
+	store.ended = true
+	store.mutex.Unlock()
+}
+
+// throwawayCopy returns a store with a copy of the values, which no other call sees. Shadow mode gives it to the
+// Lua function, so the compared call doesn't change the store a second time.
+func (store *PlaceholderExecutionStore) throwawayCopy() *PlaceholderExecutionStore {
+	if store == nil {
+		return nil
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	storeCopy := &PlaceholderExecutionStore{testCaseExecutionUUID: store.testCaseExecutionUUID,
+		values: make(map[string]PlaceholderValue, len(store.values)), ended: store.ended}
+	for key, value := range store.values {
+		storeCopy.values[key] = clonePlaceholderValue(value)
+	}
+
+	return storeCopy
+}
+
+// clonePlaceholderValue copies lists and maps, so a stored value can't be changed through the caller's value.
+func clonePlaceholderValue(value PlaceholderValue) PlaceholderValue {
+	if value.List != nil {
+		list := make([]PlaceholderValue, 0, len(value.List))
+		for _, item := range value.List {
+			list = append(list, clonePlaceholderValue(item))
+		}
+		value.List = list
+	}
+	if value.Map != nil {
+		valueMap := make(map[string]PlaceholderValue, len(value.Map))
+		for key, item := range value.Map {
+			valueMap[key] = clonePlaceholderValue(item)
+		}
+		value.Map = valueMap
+	}
+
+	return value
+}
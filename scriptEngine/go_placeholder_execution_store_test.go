@@ -0,0 +1,242 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// executionStoreLuaScript keeps an invoice number and a reserved customer in the execution store.
+const executionStoreLuaScript = `
+local fenix = require("fenix")
+
+function Test_StoreNextInvoiceNumber(inputTable)
+    local number = fenix.store():increment("invoiceNumber")
+    return { success = true, value = string.format("INV-%04d", number), errorMessage = "" }
+end
+
+function Test_StoreReserveCustomer(inputTable)
+    local store = fenix.store()
+    local customer = inputTable[3][1]
+    if store:set_if_absent("customer:" .. customer, { reserved = true, by = "lua" }) == false then
+        return { success = false, value = "", errorMessage = "customer " .. customer .. " is already reserved" }
+    end
+    return { success = true, value = customer, errorMessage = "" }
+end
+
+function Test_StoreRead(inputTable)
+    local value = fenix.store():get(inputTable[3][1])
+    if value == nil then
+        return { success = true, value = "nil", errorMessage = "" }
+    end
+    if type(value) == "table" then
+        return { success = true, value = value.by, errorMessage = "" }
+    end
+    return { success = true, value = tostring(value), errorMessage = "" }
+end
+`
+
+func registerExecutionStoreTestHandler(t *testing.T) {
+	t.Helper()
+
+	const functionName = "Test_StoreGoInvoiceNumber"
+	if err := RegisterGoPlaceholderFunction(functionName, func(input GoPlaceholderInput) (string, error) {
+		number, err := input.ExecutionStore().Increment("invoiceNumber", 1)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("INV-%04d", int(number)), nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	t.Cleanup(func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, functionName)
+		goPlaceholderFunctionsMutex.Unlock()
+	})
+}
+
+func initiateExecutionStoreTestEngine(t *testing.T, poolSize int) {
+	t.Helper()
+
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = poolSize
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "executionStore", LuaScript: []byte(executionStoreLuaScript)}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	t.Cleanup(CloseDownLuaScriptEngine)
+}
+
+func TestPlaceholderExecutionStore_ShouldBeSharedByLuaAndGoCallsOfOneExecution(t *testing.T) {
+	initiateExecutionStoreTestEngine(t, 2)
+	registerExecutionStoreTestHandler(t)
+
+	const testCaseExecutionUUID = "store-execution-shared"
+	if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution(testCaseExecutionUUID)
+
+	// The Lua and Go functions count on the same invoice number
+	var invoiceNumbers []string
+	for _, functionName := range []string{"Test_StoreNextInvoiceNumber", "Test_StoreGoInvoiceNumber", "Test_StoreNextInvoiceNumber"} {
+		value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: functionName, TestCaseExecutionUUID: testCaseExecutionUUID})
+		logPlaceholderExecutionResult(t, functionName, value, err)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		invoiceNumbers = append(invoiceNumbers, value)
+	}
+	if strings.Join(invoiceNumbers, ",") != "INV-0001,INV-0002,INV-0003" {
+		t.Fatalf("expected shared invoice numbers, got %v", invoiceNumbers)
+	}
+
+	// A value set from Go is read from Lua, and a table set from Lua is read from Go
+	store, err := GetPlaceholderExecutionStore(testCaseExecutionUUID)
+	if err != nil {
+		t.Fatalf("expected store of started execution, got: %v", err)
+	}
+	if err = store.Set("greeting", StringValue("hello")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{
+		FunctionName: "Test_StoreRead", Arguments: []string{"greeting"}, TestCaseExecutionUUID: testCaseExecutionUUID})
+	logPlaceholderExecutionResult(t, "read-go-value", value, err)
+	if err != nil || value != "hello" {
+		t.Fatalf("expected 'hello', got %q (%v)", value, err)
+	}
+
+	if _, err = ExecutePlaceholder(PlaceholderExecutionRequest{
+		FunctionName: "Test_StoreReserveCustomer", Arguments: []string{"42"}, TestCaseExecutionUUID: testCaseExecutionUUID}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	customer, exists := store.Get("customer:42")
+	t.Logf("Output [store]\n  Keys: %v\n  Customer: %s", store.Keys(), customer.Format())
+	if exists == false || customer.Format() != `{"by":"lua","reserved":true}` {
+		t.Fatalf("expected reserved customer in store, got %+v", customer)
+	}
+	_, err = ExecutePlaceholder(PlaceholderExecutionRequest{
+		FunctionName: "Test_StoreReserveCustomer", Arguments: []string{"42"}, TestCaseExecutionUUID: testCaseExecutionUUID})
+	logPlaceholderExecutionResult(t, "reserve-again", "", err)
+	if err == nil || strings.Contains(err.Error(), "customer 42 is already reserved") == false {
+		t.Fatalf("expected already reserved error, got: %v", err)
+	}
+}
+
+func TestPlaceholderExecutionStore_ShouldBeSeparatePerExecutionAndDiscardedAtEnd(t *testing.T) {
+	initiateExecutionStoreTestEngine(t, 1)
+
+	for _, testCaseExecutionUUID := range []string{"store-execution-a", "store-execution-b"} {
+		if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{}); err != nil {
+			t.Fatalf("failed to start execution: %v", err)
+		}
+		defer EndPlaceholderExecution(testCaseExecutionUUID)
+	}
+
+	executeInvoiceNumber := func(testCaseExecutionUUID string) (string, error) {
+		return ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_StoreNextInvoiceNumber", TestCaseExecutionUUID: testCaseExecutionUUID})
+	}
+	executeInvoiceNumber("store-execution-a")
+	valueA, _ := executeInvoiceNumber("store-execution-a")
+	valueB, _ := executeInvoiceNumber("store-execution-b")
+	t.Logf("Output [separate-stores]\n  Execution A: %s\n  Execution B: %s", valueA, valueB)
+	if valueA != "INV-0002" || valueB != "INV-0001" {
+		t.Fatalf("expected one store per execution, got %q and %q", valueA, valueB)
+	}
+
+	// A handler that kept the store can't use it after the execution ended
+	store, _ := GetPlaceholderExecutionStore("store-execution-a")
+	EndPlaceholderExecution("store-execution-a")
+	if _, exists := store.Get("invoiceNumber"); exists == true {
+		t.Fatalf("expected values to be discarded when the execution ended")
+	}
+	if err := store.Set("invoiceNumber", NumberValue(1)); err == nil || strings.Contains(err.Error(), "has ended") == false {
+		t.Fatalf("expected ended execution error, got: %v", err)
+	}
+	if _, err := GetPlaceholderExecutionStore("store-execution-a"); err == nil {
+		t.Fatalf("expected error for ended execution")
+	}
+
+	// Restarting the execution gives a new, empty store
+	if err := StartPlaceholderExecution("store-execution-a", PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to restart execution: %v", err)
+	}
+	if value, _ := executeInvoiceNumber("store-execution-a"); value != "INV-0001" {
+		t.Fatalf("expected new store after restart, got %q", value)
+	}
+}
+
+func TestPlaceholderExecutionStore_ShouldFailWithoutStartedExecution(t *testing.T) {
+	initiateExecutionStoreTestEngine(t, 1)
+	registerExecutionStoreTestHandler(t)
+
+	for _, functionName := range []string{"Test_StoreNextInvoiceNumber", "Test_StoreGoInvoiceNumber"} {
+		_, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: functionName, TestCaseExecutionUUID: "store-not-started"})
+		logPlaceholderExecutionResult(t, functionName, "", err)
+		if err == nil || strings.Contains(err.Error(), "StartPlaceholderExecution") == false {
+			t.Fatalf("expected not started error, got: %v", err)
+		}
+	}
+}
+
+func TestPlaceholderExecutionStore_ShouldValidateKeysAndValues(t *testing.T) {
+	const testCaseExecutionUUID = "store-execution-validation"
+	if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution(testCaseExecutionUUID)
+	store, _ := GetPlaceholderExecutionStore(testCaseExecutionUUID)
+
+	if err := store.Set(" ", StringValue("x")); err == nil {
+		t.Fatalf("expected error for empty key")
+	}
+	if err := store.Set("name", StringValue("Ada")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := store.Increment("name", 1); err == nil || strings.Contains(err.Error(), "can't increment key 'name' with a string value") == false {
+		t.Fatalf("expected increment error for string value, got: %v", err)
+	}
+
+	// Stored lists are copies, changing the caller's list doesn't change the store
+	list := ListValue(StringValue("a"))
+	if err := store.Set("list", list); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	list.List[0] = StringValue("changed")
+	if storedList, _ := store.Get("list"); storedList.List[0].String != "a" {
+		t.Fatalf("expected stored list to be a copy, got %+v", storedList)
+	}
+}
+
+func TestPlaceholderExecutionStore_ShouldGiveUniqueNumbersToConcurrentCalls(t *testing.T) {
+	initiateExecutionStoreTestEngine(t, 4)
+
+	const testCaseExecutionUUID = "store-execution-concurrent"
+	if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution(testCaseExecutionUUID)
+
+	var waitGroup sync.WaitGroup
+	invoiceNumbers := sync.Map{}
+	for callIndex := 0; callIndex < 40; callIndex++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_StoreNextInvoiceNumber", TestCaseExecutionUUID: testCaseExecutionUUID})
+			if err != nil {
+				value = err.Error()
+			}
+			invoiceNumbers.Store(value, true)
+		}()
+	}
+	waitGroup.Wait()
+
+	for number := 1; number <= 40; number++ {
+		if _, exists := invoiceNumbers.Load(fmt.Sprintf("INV-%04d", number)); exists == false {
+			t.Fatalf("expected invoice number %d to be given once", number)
+		}
+	}
+}
@@ -2,12 +2,10 @@ package scriptEngine
 
 import (
 	"fmt"
-	"hash/crc32"
 	"math/rand"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 )
 
 var (
@@ -48,12 +46,13 @@ func goFenixControlledUniqueID(input GoPlaceholderInput) (string, error) {
 		return "", fmt.Errorf("Error - third function argument must be an Integer. arguments: %v", input.Arguments)
 	}
 
-	entropyToUse := extraEntropy
-	if useEntropyFromExecutionUUID == true {
-		entropyToUse = uint64(crc32.ChecksumIEEE([]byte(input.TestCaseExecutionUUID))) + extraEntropy
-	}
+	// Entropy comes from the function arguments, not from the placeholder entropy tail.
+	entropyParameters := input.entropyParameters()
+	entropyParameters.useEntropyFromExecutionUUID = useEntropyFromExecutionUUID
+	entropyParameters.extraEntropy = extraEntropy
+	entropyToUse := deriveEntropy(input.EntropyScheme, entropyParameters)
 
-	now := currentTimeProvider().In(time.Local)
+	now := input.executionTime()
 
 	result := textToProcess
 	result = strings.ReplaceAll(result, "%YYYY-MM-DD%", now.Format("2006-01-02"))
@@ -81,7 +80,7 @@ func goFenixControlledUniqueID(input GoPlaceholderInput) (string, error) {
 	result = strings.ReplaceAll(result, "ns", fmt.Sprintf("%09d", now.Nanosecond()))
 
 	// Random value generation must stay deterministic for same array index + entropy.
-	seed := int64(arrayPositionToUse) + int64(entropyToUse)
+	seed := deriveEntropySeed(input.EntropyScheme, entropyToUse, entropyParameters, arrayPositionToUse)
 	result = replaceControlledUniqueRandomNumberPatterns(result, seed+11)
 	result = replaceControlledUniqueRandomStringPatterns(result, controlledUniqueRandomLowerPattern, "abcdefghijklmnopqrstuvwxyz", seed+13)
 	result = replaceControlledUniqueRandomStringPatterns(result, controlledUniqueRandomUpperPattern, "ABCDEFGHIJKLMNOPQRSTUVWXYZ", seed+17)
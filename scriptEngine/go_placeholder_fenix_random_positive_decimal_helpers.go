@@ -45,11 +45,11 @@ func parseRandomPositiveFunctionArguments(arguments []string) (functionArguments
 }
 
 // fenixRandomDecimalValueArrayValue generates one deterministic value for one array index.
-func fenixRandomDecimalValueArrayValue(arrayPosition int, functionArguments []int, decimalPointCharacter string, entropy uint64) string {
+func fenixRandomDecimalValueArrayValue(arrayPosition int, functionArguments []int, decimalPointCharacter string, seedForArrayIndex func(int) int64) string {
 	maxIntegerPartSize := functionArguments[0]
 	numberOfDecimals := functionArguments[1]
 
-	randomValue := randomizeDecimalValue(arrayPosition, maxIntegerPartSize, numberOfDecimals, entropy)
+	randomValue := randomizeDecimalValue(seedForArrayIndex(arrayPosition), maxIntegerPartSize, numberOfDecimals)
 	formattedValue := formatDecimalValue(randomValue, numberOfDecimals)
 	formattedValue = padValueWithZeros(formattedValue, functionArguments[2], functionArguments[3])
 
@@ -57,14 +57,14 @@ func fenixRandomDecimalValueArrayValue(arrayPosition int, functionArguments []in
 }
 
 // fenixRandomDecimalValueSumArrayValue generates per-index values and sums/subtracts them.
-func fenixRandomDecimalValueSumArrayValue(arrayPositions []int, functionArguments []int, decimalPointCharacter string, entropy uint64) string {
+func fenixRandomDecimalValueSumArrayValue(arrayPositions []int, functionArguments []int, decimalPointCharacter string, seedForArrayIndex func(int) int64) string {
 	maxIntegerPartSize := functionArguments[0]
 	numberOfDecimals := functionArguments[1]
 
 	sumOfValues := 0.0
 	for _, arrayPositionValue := range arrayPositions {
 		arrayPositionToUse := int(math.Abs(float64(arrayPositionValue)))
-		randomValue := randomizeDecimalValue(arrayPositionToUse, maxIntegerPartSize, numberOfDecimals, entropy)
+		randomValue := randomizeDecimalValue(seedForArrayIndex(arrayPositionToUse), maxIntegerPartSize, numberOfDecimals)
 
 		if arrayPositionValue >= 0 {
 			sumOfValues += randomValue
@@ -80,8 +80,7 @@ func fenixRandomDecimalValueSumArrayValue(arrayPositions []int, functionArgument
 }
 
 // randomizeDecimalValue reproduces Lua randomization shape using deterministic seeding.
-func randomizeDecimalValue(arrayIndex int, maxIntegerPartSize int, numberOfDecimals int, entropy uint64) float64 {
-	seed := int64(arrayIndex) + int64(entropy)
+func randomizeDecimalValue(seed int64, maxIntegerPartSize int, numberOfDecimals int) float64 {
 	rng := rand.New(rand.NewSource(seed))
 
 	integerMultiplier := math.Pow10(maxIntegerPartSize)
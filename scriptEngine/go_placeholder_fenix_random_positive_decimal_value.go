@@ -18,5 +18,5 @@ func goFenixRandomPositiveDecimalValue(input GoPlaceholderInput) (string, error)
 		return "", err
 	}
 
-	return fenixRandomDecimalValueArrayValue(arrayIndexToUse, functionArguments, decimalPointCharacter, input.Entropy), nil
+	return fenixRandomDecimalValueArrayValue(arrayIndexToUse, functionArguments, decimalPointCharacter, input.seedForArrayIndex), nil
 }
@@ -12,5 +12,5 @@ func goFenixRandomPositiveDecimalValueSum(input GoPlaceholderInput) (string, err
 		return "", err
 	}
 
-	return fenixRandomDecimalValueSumArrayValue(arrayIndexes, functionArguments, decimalPointCharacter, input.Entropy), nil
+	return fenixRandomDecimalValueSumArrayValue(arrayIndexes, functionArguments, decimalPointCharacter, input.seedForArrayIndex), nil
 }
@@ -128,3 +128,50 @@ func TestGoFenixRandomPositiveDecimalValue_ShouldFailOnInvalidArguments(t *testi
 		})
 	}
 }
+
+// TestGoFenixRandomPositiveDecimalValue_ShouldGiveLuaUnitTestExpectations pins the expected values in
+// luaEngine/tests/test_Fenix_RandomPositiveDecimalValue.lua to the Go handler. The Lua cases use two or four
+// parameters, which are the Go parameters with no padding and '.' as decimal point character.
+func TestGoFenixRandomPositiveDecimalValue_ShouldGiveLuaUnitTestExpectations(t *testing.T) {
+	cases := []struct {
+		luaTestName string
+		arrayIndex  []int
+		args        []string
+		entropy     uint64
+		expected    string
+	}{
+		{luaTestName: "ok_array__parameters_2_3_entropi_0", args: []string{"2", "3", "0", "0", "."}, expected: "60.940"},
+		{luaTestName: "ok_array_1_parameters_2_3_entropi_0", arrayIndex: []int{1}, args: []string{"2", "3", "0", "0", "."}, expected: "60.940"},
+		{luaTestName: "ok_array__parameters_1_2_entropi_0", args: []string{"1", "2", "0", "0", "."}, expected: "6.94"},
+		{luaTestName: "ok_array_2_parameters_1_2_entropi_0", arrayIndex: []int{2}, args: []string{"1", "2", "0", "0", "."}, expected: "1.26"},
+		{luaTestName: "ok_array__parameters_1_1_entropi_0", args: []string{"1", "1", "0", "0", "."}, expected: "6.9"},
+		{luaTestName: "ok_array__parameters_1_1_entropi_1", args: []string{"1", "1", "0", "0", "."}, entropy: 1, expected: "1.2"},
+		{luaTestName: "ok_array_1_parameters_1_0_entropi_0", arrayIndex: []int{1}, args: []string{"1", "0", "0", "0", "."}, expected: "6"},
+		{luaTestName: "ok_array_1_parameters_0_0_entropi_0", arrayIndex: []int{1}, args: []string{"0", "0", "0", "0", "."}, expected: "0"},
+		{luaTestName: "ok_array_1_parameters_0_0_2_3_entropi_0", arrayIndex: []int{1}, args: []string{"0", "0", "2", "3", "."}, expected: "00"},
+		{luaTestName: "ok_array__parameters_6_6_entropi_0", arrayIndex: []int{0}, args: []string{"6", "6", "0", "0", "."}, expected: "945196.244965"},
+		{luaTestName: "ok_array__parameters_6_10_entropi_0", arrayIndex: []int{0}, args: []string{"6", "10", "0", "0", "."}, expected: "945196.2449650852"},
+		{luaTestName: "ok_array__parameters_0_0_2_3_entropi_0", arrayIndex: []int{0}, args: []string{"0", "0", "2", "3", "."}, expected: "00"},
+		{luaTestName: "ok_array__parameters_0_2_3_4_entropi_0", arrayIndex: []int{0}, args: []string{"0", "2", "3", "4", "."}, expected: "000.2400"},
+		{luaTestName: "ok_array__parameters_2_2_3_4_entropi_0", arrayIndex: []int{0}, args: []string{"2", "2", "3", "4", "."}, expected: "094.2400"},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.luaTestName, func(t *testing.T) {
+			input := GoPlaceholderInput{
+				Placeholder:  "{{Fenix.RandomPositiveDecimalValue(" + strings.Join(testCase.args, ", ") + ")}}",
+				FunctionName: "Fenix_RandomPositiveDecimalValue",
+				ArrayIndexes: testCase.arrayIndex,
+				Arguments:    testCase.args,
+				Entropy:      testCase.entropy,
+			}
+			logPlaceholderInputMatrix(t, testCase.luaTestName, input)
+
+			result, err := goFenixRandomPositiveDecimalValue(input)
+			logPlaceholderExecutionResult(t, testCase.luaTestName, result, err)
+			if err != nil || result != testCase.expected {
+				t.Fatalf("expected %q as in the Lua unit test, got %q (%v)", testCase.expected, result, err)
+			}
+		})
+	}
+}
@@ -21,8 +21,8 @@ func goFenixTodayShiftDay(input GoPlaceholderInput) (string, error) {
 	}
 	shiftDays := parsedShift
 
-	// Work with a date-only value in local time to avoid clock-time side effects.
-	now := currentTimeProvider().In(time.Local)
+	// Work with a date-only value in the execution time zone to avoid clock-time side effects.
+	now := input.executionTime()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	return today.AddDate(0, 0, shiftDays).Format("2006-01-02"), nil
@@ -0,0 +1,138 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PlaceholderRuntime tells which runtime executes a placeholder function.
+type PlaceholderRuntime string
+
+const (
+	// PlaceholderRuntimeGo is used for functions executed by a registered Go handler.
+	PlaceholderRuntimeGo PlaceholderRuntime = "go"
+	// PlaceholderRuntimeLua is used for functions executed by the Lua engine.
+	PlaceholderRuntimeLua PlaceholderRuntime = "lua"
+)
+
+// PlaceholderHandler executes one parsed placeholder call.
+type PlaceholderHandler func(input GoPlaceholderInput) (PlaceholderValue, error)
+
+// PlaceholderCall describes the call that an interceptor wraps.
+type PlaceholderCall struct {
+	// Parsed input, including final entropy.
+	Input GoPlaceholderInput
+	// Runtime that will execute the call when the chain reaches the end.
+	Runtime PlaceholderRuntime
+}
+
+// PlaceholderInterceptor wraps placeholder execution.
+// Call next to continue the chain, possibly with modified input, or return directly to short-circuit.
+type PlaceholderInterceptor func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error)
+
+type namedPlaceholderInterceptor struct {
+	name        string
+	interceptor PlaceholderInterceptor
+}
+
+var (
+	placeholderInterceptorsMutex sync.RWMutex
+	// Ordered interceptor chain, the first entry is the outermost interceptor.
+	placeholderInterceptors []namedPlaceholderInterceptor
+)
+
+// RegisterPlaceholderInterceptor adds an interceptor to the end of the chain.
+// Registering an existing name replaces that interceptor and keeps its position.
+func RegisterPlaceholderInterceptor(name string, interceptor PlaceholderInterceptor) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("interceptor name can not be empty")
+	}
+	if interceptor == nil {
+		return fmt.Errorf("placeholder interceptor '%s' is nil", name)
+	}
+
+	placeholderInterceptorsMutex.Lock()
+	defer placeholderInterceptorsMutex.Unlock()
+
+	for index, registeredInterceptor := range placeholderInterceptors {
+		if registeredInterceptor.name == name {
+			placeholderInterceptors[index].interceptor = interceptor
+			return nil
+		}
+	}
+
+	placeholderInterceptors = append(placeholderInterceptors, namedPlaceholderInterceptor{
+		name:        name,
+		interceptor: interceptor,
+	})
+
+	return nil
+}
+
+// UnregisterPlaceholderInterceptor removes an interceptor from the chain.
+// Returns false when no interceptor with that name is registered.
+func UnregisterPlaceholderInterceptor(name string) bool {
+	placeholderInterceptorsMutex.Lock()
+	defer placeholderInterceptorsMutex.Unlock()
+
+	for index, registeredInterceptor := range placeholderInterceptors {
+		if registeredInterceptor.name == name {
+			placeholderInterceptors = append(placeholderInterceptors[:index], placeholderInterceptors[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListPlaceholderInterceptors returns the registered interceptor names in chain order.
+func ListPlaceholderInterceptors() []string {
+	placeholderInterceptorsMutex.RLock()
+	defer placeholderInterceptorsMutex.RUnlock()
+
+	names := make([]string, 0, len(placeholderInterceptors))
+	for _, registeredInterceptor := range placeholderInterceptors {
+		names = append(names, registeredInterceptor.name)
+	}
+
+	return names
+}
+
+// invokePlaceholderInterceptors runs the interceptor chain with handler as the innermost call.
+func invokePlaceholderInterceptors(call PlaceholderCall, handler PlaceholderHandler) (PlaceholderValue, error) {
+	placeholderInterceptorsMutex.RLock()
+	interceptors := append([]namedPlaceholderInterceptor{}, placeholderInterceptors...)
+	placeholderInterceptorsMutex.RUnlock()
+
+	next := handler
+	for index := len(interceptors) - 1; index >= 0; index-- {
+		next = wrapPlaceholderHandler(interceptors[index], call.Runtime, next)
+	}
+
+	return next(call.Input)
+}
+
+// wrapPlaceholderHandler binds one interceptor around next.
+// Errors that are not already classified are reported with kind 'interceptor'.
+func wrapPlaceholderHandler(interceptor namedPlaceholderInterceptor, runtime PlaceholderRuntime, next PlaceholderHandler) PlaceholderHandler {
+	return func(input GoPlaceholderInput) (value PlaceholderValue, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				value = PlaceholderValue{}
+				err = newPlaceholderExecutionError(
+					PlaceholderErrorKindPanic,
+					input.FunctionName,
+					fmt.Errorf("placeholder interceptor '%s' panicked: %v", interceptor.name, recovered))
+			}
+		}()
+
+		value, err = interceptor.interceptor(PlaceholderCall{Input: input, Runtime: runtime}, next)
+		if err != nil {
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInterceptor, input.FunctionName, err)
+		}
+
+		return value, nil
+	}
+}
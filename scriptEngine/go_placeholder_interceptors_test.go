@@ -0,0 +1,119 @@
+package scriptEngine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderInterceptors_ShouldWrapGoHandlerInRegistrationOrder(t *testing.T) {
+	var callOrder []string
+	var observedRuntime PlaceholderRuntime
+	var observedValue string
+
+	if err := RegisterPlaceholderInterceptor("test-outer", func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		callOrder = append(callOrder, "outer")
+		observedRuntime = call.Runtime
+		value, err := next(call.Input)
+		observedValue = value.Format()
+		return value, err
+	}); err != nil {
+		t.Fatalf("failed to register interceptor: %v", err)
+	}
+	defer UnregisterPlaceholderInterceptor("test-outer")
+
+	if err := RegisterPlaceholderInterceptor("test-inner", func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		callOrder = append(callOrder, "inner")
+		call.Input.Arguments = []string{"0"}
+		return next(call.Input)
+	}); err != nil {
+		t.Fatalf("failed to register interceptor: %v", err)
+	}
+	defer UnregisterPlaceholderInterceptor("test-inner")
+
+	request := PlaceholderExecutionRequest{
+		FunctionName: "Fenix_TodayShiftDay",
+		Arguments:    []string{"notAnInt"},
+	}
+	logExecutionRequest(t, "interceptor-order", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "interceptor-order", value, err)
+	if err != nil {
+		t.Fatalf("expected inner interceptor to fix the argument, got error: %v", err)
+	}
+	if strings.Join(callOrder, ",") != "outer,inner" {
+		t.Fatalf("expected call order outer,inner, got %v", callOrder)
+	}
+	if observedRuntime != PlaceholderRuntimeGo {
+		t.Fatalf("expected runtime %q, got %q", PlaceholderRuntimeGo, observedRuntime)
+	}
+	if observedValue != value {
+		t.Fatalf("expected outer interceptor to observe %q, got %q", value, observedValue)
+	}
+}
+
+func TestPlaceholderInterceptors_ShouldShortCircuitLuaFallback(t *testing.T) {
+	if err := RegisterPlaceholderInterceptor("test-override", func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		if call.Input.FunctionName == "Fenix_NotDefinedAnywhere" {
+			return StringValue("overridden:" + string(call.Runtime)), nil
+		}
+		return next(call.Input)
+	}); err != nil {
+		t.Fatalf("failed to register interceptor: %v", err)
+	}
+	defer UnregisterPlaceholderInterceptor("test-override")
+
+	request := PlaceholderExecutionRequest{FunctionName: "Fenix_NotDefinedAnywhere"}
+	logExecutionRequest(t, "short-circuit", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "short-circuit", value, err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if value != "overridden:lua" {
+		t.Fatalf("expected overridden Lua value, got %q", value)
+	}
+}
+
+func TestPlaceholderInterceptors_ShouldClassifyInterceptorErrors(t *testing.T) {
+	if err := RegisterPlaceholderInterceptor("test-fault", func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		return PlaceholderValue{}, errors.New("injected fault")
+	}); err != nil {
+		t.Fatalf("failed to register interceptor: %v", err)
+	}
+	defer UnregisterPlaceholderInterceptor("test-fault")
+
+	request := PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}}
+	logExecutionRequest(t, "fault-injection", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "fault-injection", value, err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInterceptor {
+		t.Fatalf("expected interceptor error kind, got %q (%v)", PlaceholderErrorKindOf(err), err)
+	}
+}
+
+func TestRegisterPlaceholderInterceptor_ShouldValidateAndReplaceByName(t *testing.T) {
+	if err := RegisterPlaceholderInterceptor(" ", func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		return next(call.Input)
+	}); err == nil {
+		t.Fatalf("expected error for empty interceptor name")
+	}
+	if err := RegisterPlaceholderInterceptor("test-nil", nil); err == nil {
+		t.Fatalf("expected error for nil interceptor")
+	}
+
+	passThrough := func(call PlaceholderCall, next PlaceholderHandler) (PlaceholderValue, error) {
+		return next(call.Input)
+	}
+	_ = RegisterPlaceholderInterceptor("test-first", passThrough)
+	_ = RegisterPlaceholderInterceptor("test-second", passThrough)
+	_ = RegisterPlaceholderInterceptor("test-first", passThrough)
+	defer UnregisterPlaceholderInterceptor("test-first")
+	defer UnregisterPlaceholderInterceptor("test-second")
+
+	names := ListPlaceholderInterceptors()
+	t.Logf("Registered interceptors: %v", names)
+	if strings.Join(names, ",") != "test-first,test-second" {
+		t.Fatalf("expected replaced interceptor to keep its position, got %v", names)
+	}
+}
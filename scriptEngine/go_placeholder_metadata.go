@@ -0,0 +1,263 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PlaceholderArgumentType is the type a placeholder argument must parse as.
+type PlaceholderArgumentType string
+
+const (
+	PlaceholderArgumentTypeString  PlaceholderArgumentType = "string"
+	PlaceholderArgumentTypeInteger PlaceholderArgumentType = "integer"
+	PlaceholderArgumentTypeNumber  PlaceholderArgumentType = "number"
+	PlaceholderArgumentTypeBoolean PlaceholderArgumentType = "boolean"
+)
+
+// PlaceholderArrayIndexes tells how many array indexes a placeholder function accepts.
+type PlaceholderArrayIndexes string
+
+const (
+	// PlaceholderArrayIndexesAny accepts any number of array indexes. It is used when metadata doesn't say.
+	PlaceholderArrayIndexesAny PlaceholderArrayIndexes = "any"
+	// PlaceholderArrayIndexesNone rejects array indexes.
+	PlaceholderArrayIndexesNone PlaceholderArrayIndexes = "none"
+	// PlaceholderArrayIndexesAtMostOne accepts zero or one array index.
+	PlaceholderArrayIndexesAtMostOne PlaceholderArrayIndexes = "at_most_one"
+)
+
+// PlaceholderArgumentMetadata describes one positional argument.
+type PlaceholderArgumentMetadata struct {
+	Name string
+	// Empty means string.
+	Type        PlaceholderArgumentType
+	Description string
+	// Optional arguments can be left out. They must come after all required arguments.
+	Optional bool
+}
+
+// PlaceholderFunctionMetadata describes a placeholder function of one runtime.
+// Go handlers get metadata from RegisterGoPlaceholderMetadata and Lua functions from a '<FunctionName>_meta' table.
+type PlaceholderFunctionMetadata struct {
+	// Canonical function name, for example "Fenix_TodayShiftDay".
+	FunctionName string
+	Runtime      PlaceholderRuntime
+	Description  string
+	// Positional arguments. Nil means the arguments are not checked, an empty list means the function takes none.
+	Arguments []PlaceholderArgumentMetadata
+	// Empty means any.
+	ArrayIndexes PlaceholderArrayIndexes
+	// Example placeholders, for example "{{Fenix.TodayShiftDay(1)}}".
+	Examples []string
+	// The handler validates its own input and the metadata only describes it. The built-in handlers use this,
+	// because their error texts are part of the legacy contract.
+	ValidatedByHandler bool
+	// False when the function exists but has no declared metadata.
+	Declared bool
+}
+
+var (
+	goPlaceholderMetadataMutex sync.RWMutex
+	// Metadata of Go handlers per function name.
+	goPlaceholderMetadata = map[string]PlaceholderFunctionMetadata{}
+)
+
+// RegisterGoPlaceholderMetadata sets the metadata of a Go handler. Calls that run the Go handler are validated
+// against it, and it is returned by ListPlaceholderFunctions and DescribePlaceholderFunction.
+func RegisterGoPlaceholderMetadata(functionName string, metadata PlaceholderFunctionMetadata) error {
+	functionName = strings.TrimSpace(functionName)
+	if functionName == "" {
+		return fmt.Errorf("function name can not be empty")
+	}
+
+	metadata.FunctionName = functionName
+	metadata.Runtime = PlaceholderRuntimeGo
+	metadata.Declared = true
+	if err := metadata.validate(); err != nil {
+		return fmt.Errorf("invalid metadata for placeholder function '%s': %w", functionName, err)
+	}
+
+	goPlaceholderMetadataMutex.Lock()
+	goPlaceholderMetadata[functionName] = metadata
+	goPlaceholderMetadataMutex.Unlock()
+
+	return nil
+}
+
+// validate checks argument types, argument order and the array index rule.
+func (metadata PlaceholderFunctionMetadata) validate() error {
+	optionalSeen := false
+	for argumentIndex, argument := range metadata.Arguments {
+		if strings.TrimSpace(argument.Name) == "" {
+			return fmt.Errorf("argument %d has no name", argumentIndex+1)
+		}
+		switch argument.Type {
+		case "", PlaceholderArgumentTypeString, PlaceholderArgumentTypeInteger, PlaceholderArgumentTypeNumber, PlaceholderArgumentTypeBoolean:
+		default:
+			return fmt.Errorf("argument '%s' has unknown type '%s'", argument.Name, argument.Type)
+		}
+		if argument.Optional == false && optionalSeen == true {
+			return fmt.Errorf("required argument '%s' comes after an optional argument", argument.Name)
+		}
+		optionalSeen = optionalSeen || argument.Optional
+	}
+
+	switch metadata.ArrayIndexes {
+	case "", PlaceholderArrayIndexesAny, PlaceholderArrayIndexesNone, PlaceholderArrayIndexesAtMostOne:
+		return nil
+	default:
+		return fmt.Errorf("unknown array index rule '%s'", metadata.ArrayIndexes)
+	}
+}
+
+// lookupPlaceholderMetadata returns the declared metadata of a function for one runtime.
+func lookupPlaceholderMetadata(functionName string, runtime PlaceholderRuntime) (PlaceholderFunctionMetadata, bool) {
+	if runtime == PlaceholderRuntimeLua {
+		return lookupLuaPlaceholderMetadata(functionName)
+	}
+
+	goPlaceholderMetadataMutex.RLock()
+	defer goPlaceholderMetadataMutex.RUnlock()
+
+	metadata, exists := goPlaceholderMetadata[functionName]
+	return metadata, exists
+}
+
+// validatePlaceholderInput checks the array indexes and arguments of a call against the metadata.
+func validatePlaceholderInput(metadata PlaceholderFunctionMetadata, input GoPlaceholderInput) error {
+	switch metadata.ArrayIndexes {
+	case PlaceholderArrayIndexesNone:
+		if len(input.ArrayIndexes) > 0 {
+			return fmt.Errorf("placeholder function '%s' does not support array indexes, got %v", input.FunctionName, input.ArrayIndexes)
+		}
+	case PlaceholderArrayIndexesAtMostOne:
+		if len(input.ArrayIndexes) > 1 {
+			return fmt.Errorf("placeholder function '%s' supports at most one array index, got %v", input.FunctionName, input.ArrayIndexes)
+		}
+	}
+
+	if metadata.Arguments == nil {
+		return nil
+	}
+
+	requiredArguments := 0
+	for _, argument := range metadata.Arguments {
+		if argument.Optional == false {
+			requiredArguments++
+		}
+	}
+	if len(input.Arguments) < requiredArguments || len(input.Arguments) > len(metadata.Arguments) {
+		return fmt.Errorf("placeholder function '%s' takes %s, got %d: %v",
+			input.FunctionName, describeArgumentCount(requiredArguments, len(metadata.Arguments)), len(input.Arguments), input.Arguments)
+	}
+
+	for argumentIndex, argumentValue := range input.Arguments {
+		argument := metadata.Arguments[argumentIndex]
+		if err := validatePlaceholderArgument(argument.Type, argumentValue); err != nil {
+			return fmt.Errorf("placeholder function '%s' argument %d ('%s') %v, got '%s'",
+				input.FunctionName, argumentIndex+1, argument.Name, err, argumentValue)
+		}
+	}
+
+	return nil
+}
+
+// describeArgumentCount returns, for example, "1 argument" or "2 to 3 arguments".
+func describeArgumentCount(requiredArguments int, allArguments int) string {
+	argumentWord := "arguments"
+	if allArguments == 1 {
+		argumentWord = "argument"
+	}
+	if requiredArguments == allArguments {
+		return fmt.Sprintf("%d %s", allArguments, argumentWord)
+	}
+
+	return fmt.Sprintf("%d to %d %s", requiredArguments, allArguments, argumentWord)
+}
+
+// validatePlaceholderArgument checks that an argument parses as its declared type.
+func validatePlaceholderArgument(argumentType PlaceholderArgumentType, argumentValue string) error {
+	argumentValue = strings.TrimSpace(argumentValue)
+
+	var err error
+	switch argumentType {
+	case PlaceholderArgumentTypeInteger:
+		_, err = strconv.Atoi(argumentValue)
+	case PlaceholderArgumentTypeNumber:
+		_, err = strconv.ParseFloat(argumentValue, 64)
+	case PlaceholderArgumentTypeBoolean:
+		_, err = strconv.ParseBool(argumentValue)
+	}
+	if err != nil {
+		return fmt.Errorf("must be of type %s", argumentType)
+	}
+
+	return nil
+}
+
+// validatedPlaceholderHandler checks a call against the metadata of the runtime before the handler runs.
+func validatedPlaceholderHandler(runtime PlaceholderRuntime, handler PlaceholderHandler) PlaceholderHandler {
+	return func(input GoPlaceholderInput) (PlaceholderValue, error) {
+		if metadata, exists := lookupPlaceholderMetadata(input.FunctionName, runtime); exists == true && metadata.ValidatedByHandler == false {
+			if err := validatePlaceholderInput(metadata, input); err != nil {
+				return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidInput, input.FunctionName, err)
+			}
+		}
+
+		return handler(input)
+	}
+}
+
+// ListPlaceholderFunctions returns one entry per function and runtime, for all Go handlers and Lua functions,
+// sorted by function name with Go before Lua. Functions without declared metadata have Declared false.
+func ListPlaceholderFunctions() []PlaceholderFunctionMetadata {
+	var functionMetadata []PlaceholderFunctionMetadata
+
+	goPlaceholderFunctionsMutex.RLock()
+	goFunctionNames := make([]string, 0, len(goPlaceholderFunctions))
+	for functionName := range goPlaceholderFunctions {
+		goFunctionNames = append(goFunctionNames, functionName)
+	}
+	goPlaceholderFunctionsMutex.RUnlock()
+
+	for _, functionName := range goFunctionNames {
+		functionMetadata = append(functionMetadata, describePlaceholderFunctionForRuntime(functionName, PlaceholderRuntimeGo))
+	}
+	for _, functionName := range luaPlaceholderFunctionNames() {
+		functionMetadata = append(functionMetadata, describePlaceholderFunctionForRuntime(functionName, PlaceholderRuntimeLua))
+	}
+
+	sort.Slice(functionMetadata, func(i, j int) bool {
+		if functionMetadata[i].FunctionName != functionMetadata[j].FunctionName {
+			return functionMetadata[i].FunctionName < functionMetadata[j].FunctionName
+		}
+		return functionMetadata[i].Runtime < functionMetadata[j].Runtime
+	})
+
+	return functionMetadata
+}
+
+// DescribePlaceholderFunction returns the metadata of the runtime the dispatch policy selects for a function and
+// TestData domain. False is returned when neither a Go handler nor a Lua function exists.
+func DescribePlaceholderFunction(functionName string, testDataDomainName string) (PlaceholderFunctionMetadata, bool) {
+	decision := ResolvePlaceholderDispatch(functionName, testDataDomainName)
+	if (decision.Runtime == PlaceholderRuntimeGo && decision.GoHandlerExists == false) ||
+		(decision.Runtime == PlaceholderRuntimeLua && decision.LuaFunctionExists == false) {
+		return PlaceholderFunctionMetadata{}, false
+	}
+
+	return describePlaceholderFunctionForRuntime(functionName, decision.Runtime), true
+}
+
+// describePlaceholderFunctionForRuntime returns the declared metadata, or an undeclared entry with name and runtime.
+func describePlaceholderFunctionForRuntime(functionName string, runtime PlaceholderRuntime) PlaceholderFunctionMetadata {
+	if metadata, exists := lookupPlaceholderMetadata(functionName, runtime); exists == true {
+		return metadata
+	}
+
+	return PlaceholderFunctionMetadata{FunctionName: functionName, Runtime: runtime}
+}
@@ -0,0 +1,123 @@
+package scriptEngine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterGoPlaceholderMetadata_ShouldValidateCallsBeforeHandlerRuns(t *testing.T) {
+	handlerCalls := 0
+	if err := RegisterGoPlaceholderFunction("Test_MetadataGreeting", func(input GoPlaceholderInput) (string, error) {
+		handlerCalls++
+		return strings.Repeat(input.Arguments[0], len(input.Arguments)), nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	defer func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, "Test_MetadataGreeting")
+		goPlaceholderFunctionsMutex.Unlock()
+		goPlaceholderMetadataMutex.Lock()
+		delete(goPlaceholderMetadata, "Test_MetadataGreeting")
+		goPlaceholderMetadataMutex.Unlock()
+	}()
+
+	if err := RegisterGoPlaceholderMetadata("Test_MetadataGreeting", PlaceholderFunctionMetadata{
+		Description: "Repeats the name.",
+		Arguments: []PlaceholderArgumentMetadata{
+			{Name: "Name", Type: PlaceholderArgumentTypeString},
+			{Name: "Times", Type: PlaceholderArgumentTypeInteger, Optional: true},
+		},
+		ArrayIndexes: PlaceholderArrayIndexesNone,
+	}); err != nil {
+		t.Fatalf("failed to register metadata: %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		request       PlaceholderExecutionRequest
+		expectedError string
+	}{
+		{name: "valid-required-only", request: PlaceholderExecutionRequest{Arguments: []string{"a"}}},
+		{name: "valid-with-optional", request: PlaceholderExecutionRequest{Arguments: []string{"a", " 2 "}}},
+		{name: "missing-argument", request: PlaceholderExecutionRequest{}, expectedError: "takes 1 to 2 arguments, got 0"},
+		{name: "too-many-arguments", request: PlaceholderExecutionRequest{Arguments: []string{"a", "2", "x"}}, expectedError: "takes 1 to 2 arguments, got 3"},
+		{name: "wrong-type", request: PlaceholderExecutionRequest{Arguments: []string{"a", "two"}}, expectedError: "argument 2 ('Times') must be of type integer, got 'two'"},
+		{name: "array-index", request: PlaceholderExecutionRequest{ArrayIndexes: []int{1}, Arguments: []string{"a"}}, expectedError: "does not support array indexes"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.request.FunctionName = "Test_MetadataGreeting"
+			callsBefore := handlerCalls
+
+			logExecutionRequest(t, testCase.name, testCase.request)
+			value, err := ExecutePlaceholder(testCase.request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+
+			if testCase.expectedError == "" {
+				if err != nil || handlerCalls != callsBefore+1 {
+					t.Fatalf("expected handler call without error, got: %v", err)
+				}
+				return
+			}
+			if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInvalidInput || strings.Contains(err.Error(), testCase.expectedError) == false {
+				t.Fatalf("expected invalid_input error containing %q, got: %v", testCase.expectedError, err)
+			}
+			if handlerCalls != callsBefore {
+				t.Fatalf("expected handler not to run for invalid input")
+			}
+		})
+	}
+}
+
+func TestRegisterGoPlaceholderMetadata_ShouldRejectInvalidMetadata(t *testing.T) {
+	testCases := []struct {
+		name          string
+		functionName  string
+		metadata      PlaceholderFunctionMetadata
+		expectedError string
+	}{
+		{name: "empty-function-name", functionName: " ", expectedError: "function name can not be empty"},
+		{name: "unnamed-argument", functionName: "Test_BadMetadata", metadata: PlaceholderFunctionMetadata{
+			Arguments: []PlaceholderArgumentMetadata{{Type: PlaceholderArgumentTypeString}}}, expectedError: "argument 1 has no name"},
+		{name: "unknown-type", functionName: "Test_BadMetadata", metadata: PlaceholderFunctionMetadata{
+			Arguments: []PlaceholderArgumentMetadata{{Name: "Value", Type: "date"}}}, expectedError: "unknown type 'date'"},
+		{name: "required-after-optional", functionName: "Test_BadMetadata", metadata: PlaceholderFunctionMetadata{
+			Arguments: []PlaceholderArgumentMetadata{{Name: "First", Optional: true}, {Name: "Second"}}}, expectedError: "comes after an optional argument"},
+		{name: "unknown-array-index-rule", functionName: "Test_BadMetadata", metadata: PlaceholderFunctionMetadata{
+			ArrayIndexes: "two"}, expectedError: "unknown array index rule 'two'"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := RegisterGoPlaceholderMetadata(testCase.functionName, testCase.metadata)
+			t.Logf("Output [%s]\n  Error: %v", testCase.name, err)
+			if err == nil || strings.Contains(err.Error(), testCase.expectedError) == false {
+				t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestDescribePlaceholderFunction_ShouldDescribeBuiltInGoHandlersWithoutChangingTheirErrors(t *testing.T) {
+	metadata, exists := DescribePlaceholderFunction("Fenix_TodayShiftDay", "")
+	t.Logf("Output [describe-today-shift-day]\n  Metadata: %+v", metadata)
+	if exists == false || metadata.Runtime != PlaceholderRuntimeGo || metadata.Declared == false ||
+		len(metadata.Arguments) != 1 || metadata.Arguments[0].Type != PlaceholderArgumentTypeInteger {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+
+	// Built-in handlers keep their own validation and error texts
+	_, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"x"}})
+	logPlaceholderExecutionResult(t, "built-in-validation", "", err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindHandler || strings.Contains(err.Error(), "not an Integer") == false {
+		t.Fatalf("expected handler error from built-in handler, got: %v", err)
+	}
+
+	if _, exists = DescribePlaceholderFunction("Test_DoesNotExist", ""); exists == true {
+		t.Fatalf("expected no description for unknown function")
+	}
+}
@@ -0,0 +1,214 @@
+package scriptEngine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// placeholderLatencyBucketsInSeconds are the upper bounds used for latency histograms.
+var placeholderLatencyBucketsInSeconds = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// PlaceholderLatencyBucket is one cumulative histogram bucket.
+type PlaceholderLatencyBucket struct {
+	UpperBoundSeconds float64
+	Count             uint64
+}
+
+// PlaceholderFunctionMetrics holds the collected metrics for one function name and runtime.
+type PlaceholderFunctionMetrics struct {
+	FunctionName string
+	Runtime      PlaceholderRuntime
+	// Number of executions, successful or not.
+	Calls uint64
+	// Number of failed executions per error kind.
+	ErrorsByKind map[PlaceholderErrorKind]uint64
+	// Cumulative latency buckets, same bounds for all functions.
+	LatencyBuckets []PlaceholderLatencyBucket
+	// Sum of all execution latencies.
+	LatencySumSeconds float64
+}
+
+type placeholderMetricsKey struct {
+	functionName string
+	runtime      PlaceholderRuntime
+}
+
+type placeholderMetricsCounters struct {
+	calls               uint64
+	errorsByKind        map[PlaceholderErrorKind]uint64
+	latencyBucketCounts []uint64
+	latencySumSeconds   float64
+}
+
+// placeholderMetricsUnknownFunctionName is the function name that calls to functions that don't exist are counted under.
+// The placeholder parser doesn't accept '<' and '>' in function names, and Go handlers can't be registered with it,
+// so no function is counted under the same name.
+const placeholderMetricsUnknownFunctionName = "<unknown>"
+
+var (
+	placeholderMetricsMutex sync.Mutex
+	// Metrics per function name and runtime, collected for every dispatched placeholder.
+	placeholderMetrics = map[placeholderMetricsKey]*placeholderMetricsCounters{}
+)
+
+// recordPlaceholderMetrics adds one execution to the metrics of a function name and runtime.
+// Function names without a Go handler or Lua function are counted as "<unknown>", so placeholders with
+// misspelled or generated names can't add a new series per name.
+func recordPlaceholderMetrics(functionName string, runtime PlaceholderRuntime, duration time.Duration, err error) {
+	if _, goHandlerExists := lookupGoPlaceholderFunction(functionName); goHandlerExists == false &&
+		luaPlaceholderFunctionExists(functionName) == false {
+		functionName = placeholderMetricsUnknownFunctionName
+	}
+
+	key := placeholderMetricsKey{functionName: functionName, runtime: runtime}
+	durationInSeconds := duration.Seconds()
+
+	placeholderMetricsMutex.Lock()
+	defer placeholderMetricsMutex.Unlock()
+
+	counters, exists := placeholderMetrics[key]
+	if exists == false {
+		counters = &placeholderMetricsCounters{
+			errorsByKind:        map[PlaceholderErrorKind]uint64{},
+			latencyBucketCounts: make([]uint64, len(placeholderLatencyBucketsInSeconds)),
+		}
+		placeholderMetrics[key] = counters
+	}
+
+	counters.calls++
+	counters.latencySumSeconds += durationInSeconds
+	for bucketIndex, upperBound := range placeholderLatencyBucketsInSeconds {
+		if durationInSeconds <= upperBound {
+			counters.latencyBucketCounts[bucketIndex]++
+		}
+	}
+
+	if err != nil {
+		errorKind := PlaceholderErrorKindOf(err)
+		if errorKind == "" {
+			errorKind = "unknown"
+		}
+		counters.errorsByKind[errorKind]++
+	}
+}
+
+// GetPlaceholderMetrics returns a snapshot of all collected metrics sorted by function name and runtime.
+func GetPlaceholderMetrics() []PlaceholderFunctionMetrics {
+	placeholderMetricsMutex.Lock()
+	defer placeholderMetricsMutex.Unlock()
+
+	snapshot := make([]PlaceholderFunctionMetrics, 0, len(placeholderMetrics))
+	for key, counters := range placeholderMetrics {
+		functionMetrics := PlaceholderFunctionMetrics{
+			FunctionName:      key.functionName,
+			Runtime:           key.runtime,
+			Calls:             counters.calls,
+			ErrorsByKind:      make(map[PlaceholderErrorKind]uint64, len(counters.errorsByKind)),
+			LatencyBuckets:    make([]PlaceholderLatencyBucket, 0, len(placeholderLatencyBucketsInSeconds)),
+			LatencySumSeconds: counters.latencySumSeconds,
+		}
+		for errorKind, count := range counters.errorsByKind {
+			functionMetrics.ErrorsByKind[errorKind] = count
+		}
+		for bucketIndex, upperBound := range placeholderLatencyBucketsInSeconds {
+			functionMetrics.LatencyBuckets = append(functionMetrics.LatencyBuckets, PlaceholderLatencyBucket{
+				UpperBoundSeconds: upperBound,
+				Count:             counters.latencyBucketCounts[bucketIndex],
+			})
+		}
+
+		snapshot = append(snapshot, functionMetrics)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].FunctionName != snapshot[j].FunctionName {
+			return snapshot[i].FunctionName < snapshot[j].FunctionName
+		}
+		return snapshot[i].Runtime < snapshot[j].Runtime
+	})
+
+	return snapshot
+}
+
+// ResetPlaceholderMetrics removes all collected metrics.
+func ResetPlaceholderMetrics() {
+	placeholderMetricsMutex.Lock()
+	placeholderMetrics = map[placeholderMetricsKey]*placeholderMetricsCounters{}
+	placeholderMetricsMutex.Unlock()
+}
+
+// WritePlaceholderMetricsPrometheus writes all metrics in Prometheus text exposition format.
+func WritePlaceholderMetricsPrometheus(writer io.Writer) error {
+	snapshot := GetPlaceholderMetrics()
+	bufferedWriter := bufio.NewWriter(writer)
+
+	fmt.Fprintln(bufferedWriter, "# HELP fenix_placeholder_calls_total Number of placeholder executions.")
+	fmt.Fprintln(bufferedWriter, "# TYPE fenix_placeholder_calls_total counter")
+	for _, functionMetrics := range snapshot {
+		fmt.Fprintf(bufferedWriter, "fenix_placeholder_calls_total{%s} %d\n",
+			prometheusLabels(functionMetrics.FunctionName, functionMetrics.Runtime), functionMetrics.Calls)
+	}
+
+	fmt.Fprintln(bufferedWriter, "# HELP fenix_placeholder_errors_total Number of failed placeholder executions by error kind.")
+	fmt.Fprintln(bufferedWriter, "# TYPE fenix_placeholder_errors_total counter")
+	for _, functionMetrics := range snapshot {
+		errorKinds := make([]string, 0, len(functionMetrics.ErrorsByKind))
+		for errorKind := range functionMetrics.ErrorsByKind {
+			errorKinds = append(errorKinds, string(errorKind))
+		}
+		sort.Strings(errorKinds)
+
+		for _, errorKind := range errorKinds {
+			fmt.Fprintf(bufferedWriter, "fenix_placeholder_errors_total{%s,kind=\"%s\"} %d\n",
+				prometheusLabels(functionMetrics.FunctionName, functionMetrics.Runtime),
+				escapePrometheusLabelValue(errorKind),
+				functionMetrics.ErrorsByKind[PlaceholderErrorKind(errorKind)])
+		}
+	}
+
+	fmt.Fprintln(bufferedWriter, "# HELP fenix_placeholder_duration_seconds Placeholder execution latency.")
+	fmt.Fprintln(bufferedWriter, "# TYPE fenix_placeholder_duration_seconds histogram")
+	for _, functionMetrics := range snapshot {
+		labels := prometheusLabels(functionMetrics.FunctionName, functionMetrics.Runtime)
+		for _, bucket := range functionMetrics.LatencyBuckets {
+			fmt.Fprintf(bufferedWriter, "fenix_placeholder_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bucket.UpperBoundSeconds, 'g', -1, 64), bucket.Count)
+		}
+		fmt.Fprintf(bufferedWriter, "fenix_placeholder_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, functionMetrics.Calls)
+		fmt.Fprintf(bufferedWriter, "fenix_placeholder_duration_seconds_sum{%s} %s\n",
+			labels, strconv.FormatFloat(functionMetrics.LatencySumSeconds, 'g', -1, 64))
+		fmt.Fprintf(bufferedWriter, "fenix_placeholder_duration_seconds_count{%s} %d\n", labels, functionMetrics.Calls)
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// PlaceholderMetricsHandler returns an http.Handler that serves the metrics in Prometheus text format.
+func PlaceholderMetricsHandler() http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		responseWriter.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := WritePlaceholderMetricsPrometheus(responseWriter); err != nil {
+			http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// prometheusLabels formats the shared function and runtime labels.
+func prometheusLabels(functionName string, runtime PlaceholderRuntime) string {
+	return fmt.Sprintf("function=\"%s\",runtime=\"%s\"",
+		escapePrometheusLabelValue(functionName), escapePrometheusLabelValue(string(runtime)))
+}
+
+// escapePrometheusLabelValue escapes backslash, double quote and newline as required by the text format.
+func escapePrometheusLabelValue(labelValue string) string {
+	labelValue = strings.ReplaceAll(labelValue, `\`, `\\`)
+	labelValue = strings.ReplaceAll(labelValue, `"`, `\"`)
+	return strings.ReplaceAll(labelValue, "\n", `\n`)
+}
@@ -0,0 +1,121 @@
+package scriptEngine
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderMetrics_ShouldCountCallsErrorsAndLatency(t *testing.T) {
+	ResetPlaceholderMetrics()
+	defer ResetPlaceholderMetrics()
+
+	// A function named 'unknown' is counted apart from the functions that don't exist
+	if err := RegisterGoPlaceholderFunction("unknown", func(input GoPlaceholderInput) (string, error) {
+		return "known", nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	t.Cleanup(func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, "unknown")
+		goPlaceholderFunctionsMutex.Unlock()
+	})
+	if err := RegisterGoPlaceholderFunction(placeholderMetricsUnknownFunctionName, func(input GoPlaceholderInput) (string, error) {
+		return "", nil
+	}); err == nil {
+		t.Fatalf("expected the reserved function name %q to be rejected", placeholderMetricsUnknownFunctionName)
+	}
+
+	requests := []PlaceholderExecutionRequest{
+		{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}},
+		{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"1"}},
+		{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"notAnInt"}},
+		{FunctionName: "Fenix_NotDefinedAnywhere"},
+		{FunctionName: "Fenix_AlsoNotDefinedAnywhere"},
+		{FunctionName: "unknown"},
+	}
+	for _, request := range requests {
+		logExecutionRequest(t, "metrics-call", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "metrics-call", value, err)
+	}
+
+	snapshot := GetPlaceholderMetrics()
+	t.Logf("Metrics snapshot: %+v", snapshot)
+	if len(snapshot) != 3 {
+		t.Fatalf("expected metrics for 3 function/runtime pairs, got %d", len(snapshot))
+	}
+
+	// Functions that don't exist share one entry
+	unknownMetrics := snapshot[0]
+	if unknownMetrics.FunctionName != placeholderMetricsUnknownFunctionName || unknownMetrics.Runtime != PlaceholderRuntimeLua {
+		t.Fatalf("unexpected first metrics entry: %+v", unknownMetrics)
+	}
+	if unknownMetrics.Calls != 2 || unknownMetrics.ErrorsByKind[PlaceholderErrorKindEngine] != 2 {
+		t.Fatalf("expected two engine errors for functions that don't exist, got %+v", unknownMetrics)
+	}
+
+	goMetrics := snapshot[1]
+	if goMetrics.FunctionName != "Fenix_TodayShiftDay" || goMetrics.Runtime != PlaceholderRuntimeGo {
+		t.Fatalf("unexpected second metrics entry: %+v", goMetrics)
+	}
+	if goMetrics.Calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", goMetrics.Calls)
+	}
+	if goMetrics.ErrorsByKind[PlaceholderErrorKindHandler] != 1 {
+		t.Fatalf("expected 1 handler error, got %v", goMetrics.ErrorsByKind)
+	}
+
+	namedUnknownMetrics := snapshot[2]
+	if namedUnknownMetrics.FunctionName != "unknown" || namedUnknownMetrics.Runtime != PlaceholderRuntimeGo ||
+		namedUnknownMetrics.Calls != 1 || len(namedUnknownMetrics.ErrorsByKind) != 0 {
+		t.Fatalf("expected one successful call of the function named 'unknown', got %+v", namedUnknownMetrics)
+	}
+
+	lastBucket := goMetrics.LatencyBuckets[len(goMetrics.LatencyBuckets)-1]
+	if lastBucket.Count > goMetrics.Calls {
+		t.Fatalf("cumulative bucket count %d can not exceed call count %d", lastBucket.Count, goMetrics.Calls)
+	}
+}
+
+func TestPlaceholderMetricsHandler_ShouldServePrometheusTextFormat(t *testing.T) {
+	ResetPlaceholderMetrics()
+	defer ResetPlaceholderMetrics()
+
+	request := PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"x"}}
+	logExecutionRequest(t, "prometheus", request)
+	value, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "prometheus", value, err)
+
+	recorder := httptest.NewRecorder()
+	PlaceholderMetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Result().Body)
+	t.Logf("Prometheus output:\n%s", body)
+
+	if strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") == false {
+		t.Fatalf("unexpected content type: %s", recorder.Header().Get("Content-Type"))
+	}
+
+	expectedLines := []string{
+		"# TYPE fenix_placeholder_calls_total counter",
+		`fenix_placeholder_calls_total{function="Fenix_TodayShiftDay",runtime="go"} 1`,
+		`fenix_placeholder_errors_total{function="Fenix_TodayShiftDay",runtime="go",kind="handler"} 1`,
+		"# TYPE fenix_placeholder_duration_seconds histogram",
+		`fenix_placeholder_duration_seconds_bucket{function="Fenix_TodayShiftDay",runtime="go",le="+Inf"} 1`,
+		`fenix_placeholder_duration_seconds_count{function="Fenix_TodayShiftDay",runtime="go"} 1`,
+	}
+	for _, expectedLine := range expectedLines {
+		if strings.Contains(string(body), expectedLine+"\n") == false {
+			t.Fatalf("expected output to contain line %q", expectedLine)
+		}
+	}
+}
+
+func TestEscapePrometheusLabelValue_ShouldEscapeSpecialCharacters(t *testing.T) {
+	escaped := escapePrometheusLabelValue("a\"b\\c\nd")
+	if escaped != `a\"b\\c\nd` {
+		t.Fatalf("unexpected escaped label value: %s", escaped)
+	}
+}
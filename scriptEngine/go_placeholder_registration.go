@@ -24,5 +24,62 @@ func registerDefaultGoPlaceholderFunctions() error {
 		return fmt.Errorf("failed to register placeholder 'Fenix_RandomPositiveDecimalValue_Sum': %w", err)
 	}
 
+	for _, metadata := range defaultGoPlaceholderMetadata {
+		if err := RegisterGoPlaceholderMetadata(metadata.FunctionName, metadata); err != nil {
+			return fmt.Errorf("failed to register metadata for placeholder '%s': %w", metadata.FunctionName, err)
+		}
+	}
+
 	return nil
 }
+
+// randomPositiveDecimalArguments are the arguments shared by Fenix_RandomPositiveDecimalValue and its Sum variant.
+var randomPositiveDecimalArguments = []PlaceholderArgumentMetadata{
+	{Name: "IntegerPrecision", Type: PlaceholderArgumentTypeInteger, Description: "Maximum number of random integer digits."},
+	{Name: "FractionPrecision", Type: PlaceholderArgumentTypeInteger, Description: "Number of random fraction digits."},
+	{Name: "IntegerFieldWidth", Type: PlaceholderArgumentTypeInteger, Description: "Integer part is left-padded with zeros to this width."},
+	{Name: "FractionFieldWidth", Type: PlaceholderArgumentTypeInteger, Description: "Fraction part is right-padded with zeros to this width."},
+	{Name: "DecimalPointCharacter", Type: PlaceholderArgumentTypeString, Description: "Single character used as decimal point."},
+}
+
+// defaultGoPlaceholderMetadata describes the built-in Go handlers, which validate their own input.
+var defaultGoPlaceholderMetadata = []PlaceholderFunctionMetadata{
+	{
+		FunctionName: "Fenix_TodayShiftDay",
+		Description:  "Today's date in the execution time zone shifted by a number of days, as YYYY-MM-DD.",
+		Arguments: []PlaceholderArgumentMetadata{
+			{Name: "Days", Type: PlaceholderArgumentTypeInteger, Description: "Number of days to shift, negative for past dates."},
+		},
+		ArrayIndexes:       PlaceholderArrayIndexesNone,
+		ValidatedByHandler: true,
+		Examples:           []string{"{{Fenix.TodayShiftDay(0)}}", "{{Fenix.TodayShiftDay(-1)}}"},
+	},
+	{
+		FunctionName: "Fenix_ControlledUniqueId",
+		Description:  "Text with date, time and deterministic random tokens replaced, such as %YYYY-MM-DD% and %n(5)%.",
+		Arguments: []PlaceholderArgumentMetadata{
+			{Name: "TextToProcess", Type: PlaceholderArgumentTypeString, Description: "Text with tokens."},
+			{Name: "UseEntropyFromExecutionUUID", Type: PlaceholderArgumentTypeBoolean, Description: "Whether the execution UUID contributes to the random values."},
+			{Name: "ExtraEntropy", Type: PlaceholderArgumentTypeInteger, Description: "Entropy added to the random values."},
+		},
+		ArrayIndexes:       PlaceholderArrayIndexesAtMostOne,
+		ValidatedByHandler: true,
+		Examples:           []string{"{{Fenix.ControlledUniqueId(%YYYY-MM-DD%, true, 0)}}", "{{Fenix.ControlledUniqueId[2](ID-%n(5)%-%a(4)%-%A(4)%, true, 5)}}"},
+	},
+	{
+		FunctionName:       "Fenix_RandomPositiveDecimalValue",
+		Description:        "Deterministic random positive decimal value for one array index.",
+		Arguments:          randomPositiveDecimalArguments,
+		ArrayIndexes:       PlaceholderArrayIndexesAtMostOne,
+		ValidatedByHandler: true,
+		Examples:           []string{`{{Fenix.RandomPositiveDecimalValue(2, 3, 2, 3, ".")}}`, `{{Fenix.RandomPositiveDecimalValue[2](2, 3, 2, 3, ".")}}`},
+	},
+	{
+		FunctionName:       "Fenix_RandomPositiveDecimalValue_Sum",
+		Description:        "Sum of the random decimal values of the array indexes, negative indexes are subtracted.",
+		Arguments:          randomPositiveDecimalArguments,
+		ArrayIndexes:       PlaceholderArrayIndexesAny,
+		ValidatedByHandler: true,
+		Examples:           []string{`{{Fenix.RandomPositiveDecimalValue.Sum[-1,2](2, 3, 3, 3, ".")}}`},
+	},
+}
@@ -0,0 +1,294 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PlaceholderReplayResult compares one recorded resolution with its replayed value.
+type PlaceholderReplayResult struct {
+	// Recorded resolution, nil when a replayed placeholder had no matching record.
+	Record *PlaceholderAuditRecord
+	// Input used for the replay.
+	Input GoPlaceholderInput
+	// Value and error from the replay.
+	Output string
+	Error  string
+	// True when the replay did not reproduce the recorded resolution.
+	Diverged bool
+	// Reasons for the divergence, empty when Diverged is false.
+	DivergenceReasons []string
+}
+
+// PlaceholderReplayReport is the outcome of replaying one execution.
+type PlaceholderReplayReport struct {
+	TestCaseExecutionUUID string
+	Results               []PlaceholderReplayResult
+	// Recorded resolutions that were never replayed, only used for replay sessions.
+	UnreplayedRecords []PlaceholderAuditRecord
+}
+
+// HasDivergences returns true when any replayed value differs from its recording.
+func (report PlaceholderReplayReport) HasDivergences() bool {
+	if len(report.UnreplayedRecords) > 0 {
+		return true
+	}
+	for _, result := range report.Results {
+		if result.Diverged == true {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Divergences returns only the diverging results.
+func (report PlaceholderReplayReport) Divergences() []PlaceholderReplayResult {
+	var divergences []PlaceholderReplayResult
+	for _, result := range report.Results {
+		if result.Diverged == true {
+			divergences = append(divergences, result)
+		}
+	}
+
+	return divergences
+}
+
+// ReplayPlaceholderExecution replays all records for one execution from the active audit sink.
+func ReplayPlaceholderExecution(testCaseExecutionUUID string) (report PlaceholderReplayReport, err error) {
+	records, err := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	if err != nil {
+		return report, err
+	}
+	if len(records) == 0 {
+		return report, fmt.Errorf("no recorded placeholder resolutions found for execution '%s'", testCaseExecutionUUID)
+	}
+
+	report = ReplayPlaceholderRecords(records)
+	report.TestCaseExecutionUUID = testCaseExecutionUUID
+
+	return report, nil
+}
+
+// ReplayPlaceholderRecords re-executes recorded resolutions with their recorded clock instant and entropy inputs.
+// Replayed calls are not written to the audit log.
+func ReplayPlaceholderRecords(records []PlaceholderAuditRecord) (report PlaceholderReplayReport) {
+	for recordIndex := range records {
+		record := records[recordIndex]
+		if report.TestCaseExecutionUUID == "" {
+			report.TestCaseExecutionUUID = record.TestCaseExecutionUUID
+		}
+
+		input, err := newGoPlaceholderInput(replayRequestFromRecord(record))
+		if err != nil {
+			report.Results = append(report.Results, comparePlaceholderReplay(&record, input, "", "", err))
+			continue
+		}
+
+		value, runtime, err := dispatchPlaceholderInput(input)
+		report.Results = append(report.Results, comparePlaceholderReplay(&record, input, runtime, value.Format(), err))
+	}
+
+	return report
+}
+
+// recordedEntropyScheme returns the scheme of a record.
+// Records written before entropy schemes existed were produced by the legacy scheme.
+func (record PlaceholderAuditRecord) recordedEntropyScheme() EntropyScheme {
+	if record.EntropyScheme == "" {
+		return EntropySchemeLegacy
+	}
+
+	return record.EntropyScheme
+}
+
+// replayRequestFromRecord rebuilds the request that produced a recorded resolution.
+func replayRequestFromRecord(record PlaceholderAuditRecord) PlaceholderExecutionRequest {
+	return PlaceholderExecutionRequest{
+		Placeholder:                 record.Placeholder,
+		FunctionName:                record.FunctionName,
+		ArrayIndexes:                append([]int{}, record.ArrayIndexes...),
+		Arguments:                   append([]string{}, record.Arguments...),
+		UseEntropyFromExecutionUUID: record.UseEntropyFromExecutionUUID,
+		ExtraEntropy:                record.ExtraEntropy,
+		EntropyScheme:               record.recordedEntropyScheme(),
+		EntropyScope:                record.EntropyScope,
+		EntropyScopeIDs:             record.EntropyScopeIDs,
+		TestCaseExecutionUUID:       record.TestCaseExecutionUUID,
+		ClockTime:                   record.ClockTime,
+		TestDataDomainName:          record.TestDataDomainName,
+		TestDataValues:              maps.Clone(record.TestDataValues),
+		FieldPath:                   append([]string{}, record.FieldPath...),
+	}
+}
+
+// comparePlaceholderReplay reports every difference between a recording and its replay.
+func comparePlaceholderReplay(record *PlaceholderAuditRecord, input GoPlaceholderInput, runtime PlaceholderRuntime, value string, err error) PlaceholderReplayResult {
+	result := PlaceholderReplayResult{
+		Record: record,
+		Input:  input,
+		Output: value,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if record == nil {
+		result.DivergenceReasons = append(result.DivergenceReasons, "no recorded resolution matches this placeholder")
+		result.Diverged = true
+		return result
+	}
+
+	if input.Entropy != record.Entropy {
+		result.DivergenceReasons = append(result.DivergenceReasons,
+			fmt.Sprintf("entropy derivation changed: recorded %d, replayed %d", record.Entropy, input.Entropy))
+	}
+	if runtime != record.Runtime {
+		result.DivergenceReasons = append(result.DivergenceReasons,
+			fmt.Sprintf("runtime changed: recorded '%s', replayed '%s'", record.Runtime, runtime))
+	}
+	if value != record.Output {
+		result.DivergenceReasons = append(result.DivergenceReasons,
+			fmt.Sprintf("output changed: recorded %q, replayed %q", record.Output, value))
+	}
+	if result.Error != record.Error {
+		result.DivergenceReasons = append(result.DivergenceReasons,
+			fmt.Sprintf("error changed: recorded %q, replayed %q", record.Error, result.Error))
+	}
+
+	result.Diverged = len(result.DivergenceReasons) > 0
+
+	return result
+}
+
+// placeholderReplaySession replays recorded values while a template is rendered again.
+type placeholderReplaySession struct {
+	mutex    sync.Mutex
+	records  []PlaceholderAuditRecord
+	consumed []bool
+	results  []PlaceholderReplayResult
+}
+
+var (
+	placeholderReplaySessionsMutex sync.RWMutex
+	// Active replay sessions per execution UUID.
+	placeholderReplaySessions = map[string]*placeholderReplaySession{}
+)
+
+// BeginPlaceholderReplay starts a replay session for one execution.
+// Until EndPlaceholderReplay is called, every placeholder executed with that execution UUID uses the
+// clock instant, entropy scope IDs and TestData values of its matching record, so re-rendering the same templates
+// gives byte-identical output.
+// Records are matched in recorded order on placeholder text, function name, array indexes, arguments and field path.
+func BeginPlaceholderReplay(testCaseExecutionUUID string, records []PlaceholderAuditRecord) error {
+	if strings.TrimSpace(testCaseExecutionUUID) == "" {
+		return fmt.Errorf("execution UUID can not be empty")
+	}
+
+	var recordsForExecution []PlaceholderAuditRecord
+	for _, record := range records {
+		if record.TestCaseExecutionUUID == testCaseExecutionUUID {
+			recordsForExecution = append(recordsForExecution, record)
+		}
+	}
+
+	placeholderReplaySessionsMutex.Lock()
+	defer placeholderReplaySessionsMutex.Unlock()
+
+	if _, exists := placeholderReplaySessions[testCaseExecutionUUID]; exists == true {
+		return fmt.Errorf("a replay session is already active for execution '%s'", testCaseExecutionUUID)
+	}
+
+	placeholderReplaySessions[testCaseExecutionUUID] = &placeholderReplaySession{
+		records:  recordsForExecution,
+		consumed: make([]bool, len(recordsForExecution)),
+	}
+
+	return nil
+}
+
+// EndPlaceholderReplay stops the replay session for one execution and returns its report.
+func EndPlaceholderReplay(testCaseExecutionUUID string) (report PlaceholderReplayReport, err error) {
+	placeholderReplaySessionsMutex.Lock()
+	replaySession, exists := placeholderReplaySessions[testCaseExecutionUUID]
+	delete(placeholderReplaySessions, testCaseExecutionUUID)
+	placeholderReplaySessionsMutex.Unlock()
+
+	if exists == false {
+		return report, fmt.Errorf("no replay session is active for execution '%s'", testCaseExecutionUUID)
+	}
+
+	replaySession.mutex.Lock()
+	defer replaySession.mutex.Unlock()
+
+	report.TestCaseExecutionUUID = testCaseExecutionUUID
+	report.Results = append(report.Results, replaySession.results...)
+	for recordIndex, record := range replaySession.records {
+		if replaySession.consumed[recordIndex] == false {
+			report.UnreplayedRecords = append(report.UnreplayedRecords, record)
+		}
+	}
+
+	return report, nil
+}
+
+// lookupPlaceholderReplaySession returns the active replay session for an execution, or nil.
+func lookupPlaceholderReplaySession(testCaseExecutionUUID string) *placeholderReplaySession {
+	placeholderReplaySessionsMutex.RLock()
+	defer placeholderReplaySessionsMutex.RUnlock()
+
+	return placeholderReplaySessions[testCaseExecutionUUID]
+}
+
+// execute runs one placeholder with the clock instant, entropy inputs and TestData values from its matching record
+// and stores the comparison. The scope IDs of a new rendering, such as a new template instance, are replaced by the
+// recorded ones, so scoped placeholders get their recorded entropy.
+func (replaySession *placeholderReplaySession) execute(input GoPlaceholderInput) (PlaceholderValue, error) {
+	record := replaySession.claimRecord(input)
+	if record != nil {
+		input.ClockTime = record.ClockTime
+		input.EntropyScheme = record.recordedEntropyScheme()
+		input.EntropyScope = record.EntropyScope
+		input.EntropyScopeIDs = record.EntropyScopeIDs
+		input.TestDataValues = maps.Clone(record.TestDataValues)
+		input.Entropy = deriveEntropy(input.EntropyScheme, input.entropyParameters())
+	}
+
+	value, runtime, err := dispatchPlaceholderInput(input)
+	result := comparePlaceholderReplay(record, input, runtime, value.Format(), err)
+
+	replaySession.mutex.Lock()
+	replaySession.results = append(replaySession.results, result)
+	replaySession.mutex.Unlock()
+
+	return value, err
+}
+
+// claimRecord returns the first unconsumed record matching the input and marks it as consumed.
+func (replaySession *placeholderReplaySession) claimRecord(input GoPlaceholderInput) *PlaceholderAuditRecord {
+	replaySession.mutex.Lock()
+	defer replaySession.mutex.Unlock()
+
+	for recordIndex := range replaySession.records {
+		if replaySession.consumed[recordIndex] == true {
+			continue
+		}
+
+		record := replaySession.records[recordIndex]
+		if record.Placeholder != input.Placeholder ||
+			record.FunctionName != input.FunctionName ||
+			slices.Equal(record.ArrayIndexes, input.ArrayIndexes) == false ||
+			slices.Equal(record.Arguments, input.Arguments) == false ||
+			slices.Equal(record.FieldPath, input.FieldPath) == false {
+			continue
+		}
+
+		replaySession.consumed[recordIndex] = true
+		return &record
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package scriptEngine
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func logPlaceholderReplayReport(t *testing.T, callLabel string, report PlaceholderReplayReport) {
+	t.Helper()
+	for _, result := range report.Results {
+		t.Logf(
+			"Replay result [%s]\n  Placeholder: %q\n  Output: %q\n  Error: %q\n  Diverged: %t\n  Reasons: %v",
+			callLabel,
+			result.Input.Placeholder,
+			result.Output,
+			result.Error,
+			result.Diverged,
+			result.DivergenceReasons,
+		)
+	}
+	for _, record := range report.UnreplayedRecords {
+		t.Logf("Replay result [%s]\n  Unreplayed record: %q", callLabel, record.Placeholder)
+	}
+}
+
+func replayTestRequests(testCaseExecutionUUID string) []PlaceholderExecutionRequest {
+	return []PlaceholderExecutionRequest{
+		{
+			Placeholder:                 "{{Fenix.ControlledUniqueId(ID-%YYYYMMDD%-%hhmmss%-%n(5)%, true, 3)}}",
+			FunctionName:                "Fenix_ControlledUniqueId",
+			Arguments:                   []string{"ID-%YYYYMMDD%-%hhmmss%-%n(5)%", "true", "3"},
+			UseEntropyFromExecutionUUID: true,
+			TestCaseExecutionUUID:       testCaseExecutionUUID,
+		},
+		{
+			Placeholder:                 "{{Fenix.TodayShiftDay(-2)}}",
+			FunctionName:                "Fenix_TodayShiftDay",
+			Arguments:                   []string{"-2"},
+			UseEntropyFromExecutionUUID: true,
+			TestCaseExecutionUUID:       testCaseExecutionUUID,
+		},
+		{
+			Placeholder:                 "{{Fenix.RandomPositiveDecimalValue[2](2, 3, 2, 3, \".\")}(true, 7)}",
+			FunctionName:                "Fenix_RandomPositiveDecimalValue",
+			ArrayIndexes:                []int{2},
+			Arguments:                   []string{"2", "3", "2", "3", "."},
+			UseEntropyFromExecutionUUID: true,
+			ExtraEntropy:                7,
+			TestCaseExecutionUUID:       testCaseExecutionUUID,
+		},
+	}
+}
+
+func setTestTimeProvider(instant time.Time) (restore func()) {
+	originalTimeProvider := currentTimeProvider
+	currentTimeProvider = func() time.Time {
+		return instant
+	}
+
+	return func() {
+		currentTimeProvider = originalTimeProvider
+	}
+}
+
+func recordReplayTestExecution(t *testing.T, testCaseExecutionUUID string) (outputs []string) {
+	t.Helper()
+
+	restoreTimeProvider := setTestTimeProvider(time.Date(2026, time.February, 26, 8, 23, 59, 49208485, time.Local))
+	defer restoreTimeProvider()
+
+	for _, request := range replayTestRequests(testCaseExecutionUUID) {
+		logExecutionRequest(t, "record", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "record", value, err)
+		if err != nil {
+			t.Fatalf("expected no error while recording, got: %v", err)
+		}
+		outputs = append(outputs, value)
+	}
+
+	return outputs
+}
+
+func TestReplayPlaceholderExecution_ShouldReproduceRecordedValuesOnAnotherDay(t *testing.T) {
+	sink, err := NewJSONLinesPlaceholderAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to create audit sink: %v", err)
+	}
+	defer sink.Close()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	testCaseExecutionUUID := "replay-execution-uuid"
+	recordedOutputs := recordReplayTestExecution(t, testCaseExecutionUUID)
+
+	restoreTimeProvider := setTestTimeProvider(time.Date(2027, time.July, 3, 17, 1, 2, 0, time.Local))
+	defer restoreTimeProvider()
+
+	report, err := ReplayPlaceholderExecution(testCaseExecutionUUID)
+	logPlaceholderReplayReport(t, "replay-next-day", report)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.HasDivergences() == true {
+		t.Fatalf("expected replay without divergences, got %+v", report.Divergences())
+	}
+	if len(report.Results) != len(recordedOutputs) {
+		t.Fatalf("expected %d replay results, got %d", len(recordedOutputs), len(report.Results))
+	}
+	for resultIndex, result := range report.Results {
+		if result.Output != recordedOutputs[resultIndex] {
+			t.Fatalf("expected byte-identical output %q, got %q", recordedOutputs[resultIndex], result.Output)
+		}
+	}
+
+	records, _ := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	if len(records) != len(recordedOutputs) {
+		t.Fatalf("expected replay not to write audit records, got %d records", len(records))
+	}
+}
+
+func TestReplayPlaceholderRecords_ShouldReportDivergences(t *testing.T) {
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	testCaseExecutionUUID := "replay-divergence-uuid"
+	recordReplayTestExecution(t, testCaseExecutionUUID)
+
+	records, err := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	records[0].Output = "output from an older algorithm"
+	records[2].Entropy = 1
+
+	report := ReplayPlaceholderRecords(records)
+	logPlaceholderReplayReport(t, "replay-divergence", report)
+	divergences := report.Divergences()
+	if len(divergences) != 2 {
+		t.Fatalf("expected 2 divergences, got %d", len(divergences))
+	}
+	if divergences[0].Record.Placeholder != records[0].Placeholder || divergences[1].Record.Placeholder != records[2].Placeholder {
+		t.Fatalf("unexpected diverging records: %+v", divergences)
+	}
+}
+
+func TestPlaceholderReplaySession_ShouldRenderRecordedValuesAgain(t *testing.T) {
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	testCaseExecutionUUID := "replay-session-uuid"
+	recordedOutputs := recordReplayTestExecution(t, testCaseExecutionUUID)
+	records, _ := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+
+	restoreTimeProvider := setTestTimeProvider(time.Date(2028, time.January, 1, 0, 0, 1, 0, time.Local))
+	defer restoreTimeProvider()
+
+	if err := BeginPlaceholderReplay(testCaseExecutionUUID, records); err != nil {
+		t.Fatalf("failed to begin replay: %v", err)
+	}
+	if err := BeginPlaceholderReplay(testCaseExecutionUUID, records); err == nil {
+		t.Fatalf("expected error when a replay session is already active")
+	}
+
+	requests := replayTestRequests(testCaseExecutionUUID)
+	for requestIndex, request := range requests[:2] {
+		logExecutionRequest(t, "replay-session", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "replay-session", value, err)
+		if value != recordedOutputs[requestIndex] {
+			t.Fatalf("expected replayed output %q, got %q", recordedOutputs[requestIndex], value)
+		}
+	}
+
+	report, err := EndPlaceholderReplay(testCaseExecutionUUID)
+	logPlaceholderReplayReport(t, "replay-session", report)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(report.Divergences()) != 0 {
+		t.Fatalf("expected no diverging results, got %+v", report.Divergences())
+	}
+	if len(report.UnreplayedRecords) != 1 || report.HasDivergences() == false {
+		t.Fatalf("expected the third record to be reported as not replayed, got %+v", report.UnreplayedRecords)
+	}
+	if _, err = EndPlaceholderReplay(testCaseExecutionUUID); err == nil {
+		t.Fatalf("expected error when ending a replay session twice")
+	}
+}
+
+// replayTestDataLuaScript reads a TestData value of the rendering.
+const replayTestDataLuaScript = `
+local fenix = require("fenix")
+
+function Test_ReplayTestData(inputTable)
+    return { success = true, value = "account " .. tostring(fenix.testdata("AccountNumber")), errorMessage = "" }
+end
+`
+
+func TestReplayPlaceholderExecution_ShouldReplayTestDataValuesOfLuaFunctions(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "replayTestData", LuaScript: []byte(replayTestDataLuaScript)}}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	const testCaseExecutionUUID = "replay-testdata-uuid"
+	request := PlaceholderExecutionRequest{
+		Placeholder:           "{{Test.ReplayTestData()}}",
+		FunctionName:          "Test_ReplayTestData",
+		TestCaseExecutionUUID: testCaseExecutionUUID,
+		TestDataValues:        map[string]string{"AccountNumber": "1234-5678"},
+	}
+	recordedValue, err := ExecutePlaceholder(request)
+	logPlaceholderExecutionResult(t, "record", recordedValue, err)
+	if err != nil || recordedValue != "account 1234-5678" {
+		t.Fatalf("expected TestData value while recording, got %q (%v)", recordedValue, err)
+	}
+
+	records, _ := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+	if len(records) != 1 || maps.Equal(records[0].TestDataValues, request.TestDataValues) == false {
+		t.Fatalf("expected TestData values in the audit record, got %+v", records)
+	}
+
+	// The replay gets the recorded TestData values, not an empty input
+	report, err := ReplayPlaceholderExecution(testCaseExecutionUUID)
+	logPlaceholderReplayReport(t, "replay-testdata", report)
+	if err != nil || report.HasDivergences() == true || len(report.Results) != 1 || report.Results[0].Output != recordedValue {
+		t.Fatalf("expected replay without divergences, got %+v (%v)", report.Results, err)
+	}
+}
+
+func TestPlaceholderReplaySession_ShouldUseRecordedScopeIDsAndTestDataValues(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "replayTestData", LuaScript: []byte(replayTestDataLuaScript)}}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	const testCaseExecutionUUID = "replay-session-scope-uuid"
+	renderRequests := func(templateInstanceID string, accountNumber string) []PlaceholderExecutionRequest {
+		return []PlaceholderExecutionRequest{
+			{
+				Placeholder:                 "{{Fenix.RandomPositiveDecimalValue(4, 2, 4, 2, \".\")(true, template)}}",
+				FunctionName:                "Fenix_RandomPositiveDecimalValue",
+				Arguments:                   []string{"4", "2", "4", "2", "."},
+				UseEntropyFromExecutionUUID: true,
+				EntropyScope:                EntropyScopeTemplate,
+				EntropyScopeIDs:             EntropyScopeIDs{TemplateInstanceID: templateInstanceID},
+				TestCaseExecutionUUID:       testCaseExecutionUUID,
+			},
+			{
+				Placeholder:           "{{Test.ReplayTestData()}}",
+				FunctionName:          "Test_ReplayTestData",
+				TestCaseExecutionUUID: testCaseExecutionUUID,
+				TestDataValues:        map[string]string{"AccountNumber": accountNumber},
+			},
+		}
+	}
+
+	var recordedOutputs []string
+	for _, request := range renderRequests("template-instance-1", "1234-5678") {
+		logExecutionRequest(t, "record-scoped", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "record-scoped", value, err)
+		if err != nil {
+			t.Fatalf("expected no error while recording, got: %v", err)
+		}
+		recordedOutputs = append(recordedOutputs, value)
+	}
+	records, _ := GetPlaceholderAuditRecords(testCaseExecutionUUID)
+
+	if err := BeginPlaceholderReplay(testCaseExecutionUUID, records); err != nil {
+		t.Fatalf("failed to begin replay: %v", err)
+	}
+
+	// The re-rendering is a new template instance with other TestData, the recorded values are used
+	for requestIndex, request := range renderRequests("template-instance-2", "9999-0000") {
+		logExecutionRequest(t, "replay-session-scoped", request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, "replay-session-scoped", value, err)
+		if err != nil || value != recordedOutputs[requestIndex] {
+			t.Fatalf("expected replayed output %q, got %q (%v)", recordedOutputs[requestIndex], value, err)
+		}
+	}
+
+	report, err := EndPlaceholderReplay(testCaseExecutionUUID)
+	logPlaceholderReplayReport(t, "replay-session-scoped", report)
+	if err != nil || report.HasDivergences() == true {
+		t.Fatalf("expected replay without divergences, got %+v (%v)", report, err)
+	}
+}
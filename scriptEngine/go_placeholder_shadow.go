@@ -0,0 +1,188 @@
+package scriptEngine
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxPlaceholderShadowDivergences bounds the number of divergences kept in memory.
+const maxPlaceholderShadowDivergences = 1000
+
+// PlaceholderShadowDivergence is one call where the Go handler and the Lua function disagreed.
+type PlaceholderShadowDivergence struct {
+	// Input given to both runtimes.
+	Input GoPlaceholderInput
+	// Formatted value and error text from the Go handler, which is what the caller received.
+	GoValue string
+	GoError string
+	// Formatted value and error text from the Lua function.
+	LuaValue string
+	LuaError string
+}
+
+// PlaceholderShadowFunctionSummary counts shadow comparisons for one function.
+type PlaceholderShadowFunctionSummary struct {
+	FunctionName string
+	// Calls where both runtimes were executed.
+	Comparisons uint64
+	// Comparisons where value and error text were equal.
+	Matches uint64
+	// Comparisons where value or error text differed.
+	Divergences uint64
+	// Calls where the Lua function could not be executed, for example because it is not loaded.
+	LuaUnavailable uint64
+}
+
+// PlaceholderShadowReport is the outcome of shadow mode since the last reset.
+type PlaceholderShadowReport struct {
+	// One summary per function, sorted by function name.
+	Functions []PlaceholderShadowFunctionSummary
+	// Diverging calls in call order, at most maxPlaceholderShadowDivergences.
+	Divergences []PlaceholderShadowDivergence
+	// Divergences that were counted but not kept because the limit was reached.
+	DroppedDivergences uint64
+}
+
+var (
+	placeholderShadowMutex sync.RWMutex
+	// True while shadow mode is enabled.
+	placeholderShadowEnabled bool
+	// Functions compared in shadow mode. Empty means every Go handler.
+	placeholderShadowFunctionNames = map[string]bool{}
+
+	placeholderShadowReportMutex sync.Mutex
+	// Counters and divergences per function since the last reset.
+	placeholderShadowSummaries   = map[string]*PlaceholderShadowFunctionSummary{}
+	placeholderShadowDivergences []PlaceholderShadowDivergence
+	placeholderShadowDropped     uint64
+)
+
+// EnablePlaceholderShadowMode executes the Lua function next to every Go handler and records divergences.
+// The Go value is always returned. Without function names every Go handler with a Lua counterpart is compared.
+func EnablePlaceholderShadowMode(functionNames ...string) {
+	placeholderShadowMutex.Lock()
+	defer placeholderShadowMutex.Unlock()
+
+	placeholderShadowEnabled = true
+	placeholderShadowFunctionNames = map[string]bool{}
+	for _, functionName := range functionNames {
+		placeholderShadowFunctionNames[strings.TrimSpace(functionName)] = true
+	}
+}
+
+// DisablePlaceholderShadowMode stops shadow execution. The report is kept until ResetPlaceholderShadowReport.
+func DisablePlaceholderShadowMode() {
+	placeholderShadowMutex.Lock()
+	placeholderShadowEnabled = false
+	placeholderShadowFunctionNames = map[string]bool{}
+	placeholderShadowMutex.Unlock()
+}
+
+// GetPlaceholderShadowReport returns a copy of the shadow comparisons since the last reset.
+func GetPlaceholderShadowReport() PlaceholderShadowReport {
+	placeholderShadowReportMutex.Lock()
+	defer placeholderShadowReportMutex.Unlock()
+
+	report := PlaceholderShadowReport{
+		Divergences:        append([]PlaceholderShadowDivergence{}, placeholderShadowDivergences...),
+		DroppedDivergences: placeholderShadowDropped,
+	}
+	for _, summary := range placeholderShadowSummaries {
+		report.Functions = append(report.Functions, *summary)
+	}
+	sort.Slice(report.Functions, func(i, j int) bool {
+		return report.Functions[i].FunctionName < report.Functions[j].FunctionName
+	})
+
+	return report
+}
+
+// ResetPlaceholderShadowReport clears all shadow comparisons.
+func ResetPlaceholderShadowReport() {
+	placeholderShadowReportMutex.Lock()
+	placeholderShadowSummaries = map[string]*PlaceholderShadowFunctionSummary{}
+	placeholderShadowDivergences = nil
+	placeholderShadowDropped = 0
+	placeholderShadowReportMutex.Unlock()
+}
+
+// isPlaceholderShadowed returns true when shadow mode compares the function.
+func isPlaceholderShadowed(functionName string) bool {
+	placeholderShadowMutex.RLock()
+	defer placeholderShadowMutex.RUnlock()
+
+	if placeholderShadowEnabled == false {
+		return false
+	}
+
+	return len(placeholderShadowFunctionNames) == 0 || placeholderShadowFunctionNames[functionName] == true
+}
+
+// shadowPlaceholderHandler wraps a Go handler so the Lua function runs for the same input.
+// The Lua function gets a copy of the execution store taken before the Go handler runs, so it sees the same values
+// and its changes are dropped. Its Lua output is captured apart, so the caller only gets the output of the Go value.
+func shadowPlaceholderHandler(goHandler PlaceholderHandler) PlaceholderHandler {
+	return func(input GoPlaceholderInput) (PlaceholderValue, error) {
+		luaInput := input
+		luaInput.executionStore = input.executionStore.throwawayCopy()
+		luaInput.luaOutput = &luaOutputCapture{}
+
+		goValue, goErr := goHandler(input)
+		luaValue, luaErr := executeLuaPlaceholderFunction(luaInput)
+		recordPlaceholderShadowComparison(input, goValue.Format(), goErr, luaValue.Format(), luaErr)
+
+		return goValue, goErr
+	}
+}
+
+// recordPlaceholderShadowComparison compares one Go and Lua result and updates the report.
+func recordPlaceholderShadowComparison(input GoPlaceholderInput, goValue string, goErr error, luaValue string, luaErr error) {
+	placeholderShadowReportMutex.Lock()
+	defer placeholderShadowReportMutex.Unlock()
+
+	summary, exists := placeholderShadowSummaries[input.FunctionName]
+	if exists == false {
+		summary = &PlaceholderShadowFunctionSummary{FunctionName: input.FunctionName}
+		placeholderShadowSummaries[input.FunctionName] = summary
+	}
+
+	switch PlaceholderErrorKindOf(luaErr) {
+	case PlaceholderErrorKindEngine, PlaceholderErrorKindFunctionNotFound:
+		summary.LuaUnavailable++
+		return
+	}
+
+	summary.Comparisons++
+	goError, luaError := errorText(goErr), errorText(luaErr)
+	if goValue == luaValue && goError == luaError {
+		summary.Matches++
+		return
+	}
+
+	summary.Divergences++
+	if len(placeholderShadowDivergences) >= maxPlaceholderShadowDivergences {
+		placeholderShadowDropped++
+		return
+	}
+
+	divergenceInput := input
+	divergenceInput.ArrayIndexes = append([]int{}, input.ArrayIndexes...)
+	divergenceInput.Arguments = append([]string{}, input.Arguments...)
+	placeholderShadowDivergences = append(placeholderShadowDivergences, PlaceholderShadowDivergence{
+		Input:    divergenceInput,
+		GoValue:  goValue,
+		GoError:  goError,
+		LuaValue: luaValue,
+		LuaError: luaError,
+	})
+}
+
+// errorText returns the error message, or "" for nil.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,257 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// shadowTestLuaScript returns the first argument in upper case, or an error when it is "fail".
+const shadowTestLuaScript = `
+function Test_ShadowUpper(inputTable)
+    local functionArgs = inputTable[3]
+    if functionArgs[1] == "fail" then
+        return { success = false, value = "", errorMessage = "lua refuses 'fail'" }
+    end
+    return { success = true, value = string.upper(functionArgs[1]), errorMessage = "" }
+end
+`
+
+func logPlaceholderShadowReport(t *testing.T, callLabel string, report PlaceholderShadowReport) {
+	t.Helper()
+	for _, summary := range report.Functions {
+		t.Logf(
+			"Shadow summary [%s]\n  FunctionName: %q\n  Comparisons: %d\n  Matches: %d\n  Divergences: %d\n  LuaUnavailable: %d",
+			callLabel,
+			summary.FunctionName,
+			summary.Comparisons,
+			summary.Matches,
+			summary.Divergences,
+			summary.LuaUnavailable,
+		)
+	}
+	for _, divergence := range report.Divergences {
+		t.Logf(
+			"Shadow divergence [%s]\n  Arguments: %v\n  GoValue: %q\n  GoError: %q\n  LuaValue: %q\n  LuaError: %q",
+			callLabel,
+			divergence.Input.Arguments,
+			divergence.GoValue,
+			divergence.GoError,
+			divergence.LuaValue,
+			divergence.LuaError,
+		)
+	}
+}
+
+func registerShadowTestHandler(t *testing.T, functionName string) {
+	t.Helper()
+
+	// The Go mirror diverges on "diverge" and only fails where Lua fails.
+	if err := RegisterGoPlaceholderFunction(functionName, func(input GoPlaceholderInput) (string, error) {
+		switch input.Arguments[0] {
+		case "diverge":
+			return "GO-ONLY", nil
+		case "fail":
+			return "", fmt.Errorf("lua refuses 'fail'")
+		}
+		return strings.ToUpper(input.Arguments[0]), nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	t.Cleanup(func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, functionName)
+		goPlaceholderFunctionsMutex.Unlock()
+	})
+}
+
+func TestPlaceholderShadowMode_ShouldReturnGoValueAndReportDivergences(t *testing.T) {
+	registerShadowTestHandler(t, "Test_ShadowUpper")
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{{LuaScriptName: "shadowTest", LuaScript: []byte(shadowTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	EnablePlaceholderShadowMode("Test_ShadowUpper")
+	defer DisablePlaceholderShadowMode()
+	ResetPlaceholderShadowReport()
+	defer ResetPlaceholderShadowReport()
+
+	testCases := []struct {
+		argument      string
+		expectedValue string
+	}{
+		{argument: "abc", expectedValue: "ABC"},
+		{argument: "diverge", expectedValue: "GO-ONLY"},
+		{argument: "fail", expectedValue: ""},
+	}
+	for _, testCase := range testCases {
+		request := PlaceholderExecutionRequest{FunctionName: "Test_ShadowUpper", Arguments: []string{testCase.argument}}
+		logExecutionRequest(t, testCase.argument, request)
+		value, err := ExecutePlaceholder(request)
+		logPlaceholderExecutionResult(t, testCase.argument, value, err)
+		if value != testCase.expectedValue {
+			t.Fatalf("expected Go value %q, got %q", testCase.expectedValue, value)
+		}
+	}
+
+	report := GetPlaceholderShadowReport()
+	logPlaceholderShadowReport(t, "shadow", report)
+	if len(report.Functions) != 1 {
+		t.Fatalf("expected one shadowed function, got %+v", report.Functions)
+	}
+	summary := report.Functions[0]
+	if summary.Comparisons != 3 || summary.Matches != 2 || summary.Divergences != 1 {
+		t.Fatalf("unexpected shadow summary: %+v", summary)
+	}
+	if len(report.Divergences) != 1 || report.Divergences[0].LuaValue != "DIVERGE" || report.Divergences[0].GoValue != "GO-ONLY" {
+		t.Fatalf("expected the diverging input with both values, got %+v", report.Divergences)
+	}
+}
+
+func TestPlaceholderShadowMode_ShouldCountUnavailableLuaFunctions(t *testing.T) {
+	registerShadowTestHandler(t, "Test_ShadowWithoutLua")
+	CloseDownLuaScriptEngine()
+
+	EnablePlaceholderShadowMode()
+	defer DisablePlaceholderShadowMode()
+	ResetPlaceholderShadowReport()
+	defer ResetPlaceholderShadowReport()
+
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ShadowWithoutLua", Arguments: []string{"abc"}})
+	logPlaceholderExecutionResult(t, "shadow-without-lua", value, err)
+	if err != nil || value != "ABC" {
+		t.Fatalf("expected Go value without error, got %q (%v)", value, err)
+	}
+
+	report := GetPlaceholderShadowReport()
+	logPlaceholderShadowReport(t, "shadow-without-lua", report)
+	if len(report.Functions) != 1 || report.Functions[0].LuaUnavailable != 1 || report.Functions[0].Comparisons != 0 {
+		t.Fatalf("expected one unavailable Lua call, got %+v", report.Functions)
+	}
+}
+
+func TestPlaceholderShadowMode_ShouldOnlyShadowSelectedFunctions(t *testing.T) {
+	registerShadowTestHandler(t, "Test_ShadowNotSelected")
+
+	EnablePlaceholderShadowMode("Some_Other_Function")
+	defer DisablePlaceholderShadowMode()
+	ResetPlaceholderShadowReport()
+	defer ResetPlaceholderShadowReport()
+
+	_, _ = ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ShadowNotSelected", Arguments: []string{"abc"}})
+	DisablePlaceholderShadowMode()
+	_, _ = ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ShadowNotSelected", Arguments: []string{"abc"}})
+
+	report := GetPlaceholderShadowReport()
+	logPlaceholderShadowReport(t, "shadow-not-selected", report)
+	if len(report.Functions) != 0 {
+		t.Fatalf("expected no shadow comparisons, got %+v", report.Functions)
+	}
+}
+
+// shadowStoreTestLuaScript counts calls in the execution store, like its Go mirror.
+const shadowStoreTestLuaScript = `
+local fenix = require("fenix")
+
+function Test_ShadowCounter(inputTable)
+    return { success = true, value = string.format("%d", fenix.store():increment("counter")), errorMessage = "" }
+end
+`
+
+func TestPlaceholderShadowMode_ShouldNotChangeTheExecutionStoreTwice(t *testing.T) {
+	const functionName = "Test_ShadowCounter"
+	if err := RegisterGoPlaceholderFunction(functionName, func(input GoPlaceholderInput) (string, error) {
+		number, err := input.ExecutionStore().Increment("counter", 1)
+		return fmt.Sprintf("%d", int(number)), err
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	t.Cleanup(func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, functionName)
+		goPlaceholderFunctionsMutex.Unlock()
+	})
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{{LuaScriptName: "shadowStoreTest", LuaScript: []byte(shadowStoreTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	EnablePlaceholderShadowMode(functionName)
+	defer DisablePlaceholderShadowMode()
+	ResetPlaceholderShadowReport()
+	defer ResetPlaceholderShadowReport()
+
+	const testCaseExecutionUUID = "shadow-store-execution"
+	if err := StartPlaceholderExecution(testCaseExecutionUUID, PlaceholderExecutionOptions{}); err != nil {
+		t.Fatalf("failed to start execution: %v", err)
+	}
+	defer EndPlaceholderExecution(testCaseExecutionUUID)
+
+	for _, expectedValue := range []string{"1", "2", "3"} {
+		value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: functionName, TestCaseExecutionUUID: testCaseExecutionUUID})
+		logPlaceholderExecutionResult(t, "counter-"+expectedValue, value, err)
+		if err != nil || value != expectedValue {
+			t.Fatalf("expected %q, got %q (%v)", expectedValue, value, err)
+		}
+	}
+
+	// Lua counted from the same values as Go, and only Go's increments were kept
+	report := GetPlaceholderShadowReport()
+	logPlaceholderShadowReport(t, "shadow-store", report)
+	if len(report.Functions) != 1 || report.Functions[0].Matches != 3 || report.Functions[0].Divergences != 0 {
+		t.Fatalf("expected three matching comparisons, got %+v", report.Functions)
+	}
+	store, _ := GetPlaceholderExecutionStore(testCaseExecutionUUID)
+	if counter, _ := store.Get("counter"); counter.Format() != "3" {
+		t.Fatalf("expected the store to be changed once per call, got %q", counter.Format())
+	}
+}
+
+// shadowOutputTestLuaScript prints and logs while it gives the same value as the Go handler.
+const shadowOutputTestLuaScript = `
+local fenix = require("fenix")
+
+function Test_ShadowUpper(inputTable)
+    print("shadow print")
+    fenix.log("info", "shadow log")
+    return { success = true, value = string.upper(inputTable[3][1]), errorMessage = "" }
+end
+`
+
+func TestPlaceholderShadowMode_ShouldNotReturnLuaOutputOfTheShadowCall(t *testing.T) {
+	registerShadowTestHandler(t, "Test_ShadowUpper")
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{{LuaScriptName: "shadowOutputTest", LuaScript: []byte(shadowOutputTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	EnablePlaceholderShadowMode("Test_ShadowUpper")
+	defer DisablePlaceholderShadowMode()
+	ResetPlaceholderShadowReport()
+	defer ResetPlaceholderShadowReport()
+
+	request := PlaceholderExecutionRequest{FunctionName: "Test_ShadowUpper", Arguments: []string{"quiet"}, TestCaseExecutionUUID: "shadow-output-execution"}
+	logExecutionRequest(t, "shadow-output", request)
+	result, err := ExecutePlaceholderWithResult(request)
+	logPlaceholderExecutionResult(t, "shadow-output", result.Value.Format(), err)
+	t.Logf("Output [shadow-output]\n  LuaOutput: %+v", result.LuaOutput)
+	if err != nil || result.Value.Format() != "QUIET" {
+		t.Fatalf("expected the Go value, got %q (%v)", result.Value.Format(), err)
+	}
+
+	// The shadow call ran, but its output belongs to neither the result nor the audit record
+	if report := GetPlaceholderShadowReport(); len(report.Functions) != 1 || report.Functions[0].Matches != 1 {
+		t.Fatalf("expected one matching comparison, got %+v", report.Functions)
+	}
+	if len(result.LuaOutput) != 0 {
+		t.Fatalf("expected no Lua output in the result, got %+v", result.LuaOutput)
+	}
+	records, _ := GetPlaceholderAuditRecords(request.TestCaseExecutionUUID)
+	if len(records) != 1 || len(records[0].LuaOutput) != 0 {
+		t.Fatalf("expected one audit record without Lua output, got %+v", records)
+	}
+}
@@ -3,3 +3,13 @@ package scriptEngine
 import "time"
 
 var currentTimeProvider = time.Now
+
+// executionTime returns the clock instant used by date/time handlers for this call.
+// Input built by the dispatcher carries the instant, handlers called directly fall back to the time provider.
+func (input GoPlaceholderInput) executionTime() time.Time {
+	if input.ClockTime.IsZero() == true {
+		return currentTimeProvider().In(time.Local)
+	}
+
+	return input.ClockTime
+}
@@ -0,0 +1,151 @@
+package scriptEngine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderValueKind tells which field of a PlaceholderValue holds the value.
+type PlaceholderValueKind string
+
+const (
+	PlaceholderValueKindString PlaceholderValueKind = "string"
+	PlaceholderValueKindNumber PlaceholderValueKind = "number"
+	PlaceholderValueKindBool   PlaceholderValueKind = "bool"
+	PlaceholderValueKindList   PlaceholderValueKind = "list"
+	PlaceholderValueKindMap    PlaceholderValueKind = "map"
+)
+
+// PlaceholderValue is the typed result of a placeholder function.
+// It is formatted into text when the template is rendered. The zero value is an empty string.
+type PlaceholderValue struct {
+	Kind   PlaceholderValueKind
+	String string
+	Number float64
+	Bool   bool
+	List   []PlaceholderValue
+	Map    map[string]PlaceholderValue
+}
+
+// StringValue returns a string placeholder value.
+func StringValue(value string) PlaceholderValue {
+	return PlaceholderValue{Kind: PlaceholderValueKindString, String: value}
+}
+
+// NumberValue returns a number placeholder value.
+func NumberValue(value float64) PlaceholderValue {
+	return PlaceholderValue{Kind: PlaceholderValueKindNumber, Number: value}
+}
+
+// BoolValue returns a boolean placeholder value.
+func BoolValue(value bool) PlaceholderValue {
+	return PlaceholderValue{Kind: PlaceholderValueKindBool, Bool: value}
+}
+
+// ListValue returns a list placeholder value.
+func ListValue(values ...PlaceholderValue) PlaceholderValue {
+	return PlaceholderValue{Kind: PlaceholderValueKindList, List: values}
+}
+
+// MapValue returns a map placeholder value, for example a record with street, zip and city.
+func MapValue(values map[string]PlaceholderValue) PlaceholderValue {
+	return PlaceholderValue{Kind: PlaceholderValueKindMap, Map: values}
+}
+
+// kind returns the value kind, where the zero value counts as string.
+func (value PlaceholderValue) kind() PlaceholderValueKind {
+	if value.Kind == "" {
+		return PlaceholderValueKindString
+	}
+
+	return value.Kind
+}
+
+// Format returns the text written into the rendered template.
+// Strings are written as is, numbers in their shortest form, booleans as true/false,
+// and lists and maps as JSON with map keys sorted.
+func (value PlaceholderValue) Format() string {
+	switch value.kind() {
+	case PlaceholderValueKindNumber:
+		return strconv.FormatFloat(value.Number, 'f', -1, 64)
+	case PlaceholderValueKindBool:
+		return strconv.FormatBool(value.Bool)
+	case PlaceholderValueKindList, PlaceholderValueKindMap:
+		formattedValue, err := json.Marshal(value.Native())
+		if err != nil {
+			return err.Error()
+		}
+		return string(formattedValue)
+	default:
+		return value.String
+	}
+}
+
+// Native converts the value into string, float64, bool, []interface{} or map[string]interface{}.
+func (value PlaceholderValue) Native() interface{} {
+	switch value.kind() {
+	case PlaceholderValueKindNumber:
+		return value.Number
+	case PlaceholderValueKindBool:
+		return value.Bool
+	case PlaceholderValueKindList:
+		nativeList := make([]interface{}, 0, len(value.List))
+		for _, item := range value.List {
+			nativeList = append(nativeList, item.Native())
+		}
+		return nativeList
+	case PlaceholderValueKindMap:
+		nativeMap := make(map[string]interface{}, len(value.Map))
+		for key, item := range value.Map {
+			nativeMap[key] = item.Native()
+		}
+		return nativeMap
+	default:
+		return value.String
+	}
+}
+
+// Field returns the value at a field path. Map values are selected by key and list values by
+// 1-based position, the same way as Lua tables and placeholder array indexes.
+func (value PlaceholderValue) Field(fieldPath ...string) (PlaceholderValue, error) {
+	current := value
+	for pathIndex, field := range fieldPath {
+		switch current.kind() {
+		case PlaceholderValueKindMap:
+			next, exists := current.Map[field]
+			if exists == false {
+				return PlaceholderValue{}, fmt.Errorf("field '%s' does not exist in '%s', available fields: %s",
+					field, strings.Join(fieldPath[:pathIndex+1], "."), strings.Join(current.mapKeys(), ", "))
+			}
+			current = next
+
+		case PlaceholderValueKindList:
+			position, err := strconv.Atoi(field)
+			if err != nil || position < 1 || position > len(current.List) {
+				return PlaceholderValue{}, fmt.Errorf("list position '%s' in '%s' must be between 1 and %d",
+					field, strings.Join(fieldPath[:pathIndex+1], "."), len(current.List))
+			}
+			current = current.List[position-1]
+
+		default:
+			return PlaceholderValue{}, fmt.Errorf("can't access field '%s' in '%s' of a %s value",
+				field, strings.Join(fieldPath[:pathIndex+1], "."), current.kind())
+		}
+	}
+
+	return current, nil
+}
+
+// mapKeys returns the sorted keys of a map value.
+func (value PlaceholderValue) mapKeys() []string {
+	keys := make([]string, 0, len(value.Map))
+	for key := range value.Map {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,200 @@
+package scriptEngine
+
+import (
+	"strings"
+	"testing"
+)
+
+// valueTestLuaScript returns the Lua variants of a structured address and scalar values.
+const valueTestLuaScript = `
+function Test_LuaAddress(inputTable)
+    return { success = true, value = { street = "Main Street 1", zip = 12345, lines = { "Main Street 1", "Stockholm" } }, errorMessage = "" }
+end
+
+function Test_LuaScalar(inputTable)
+    local functionArgs = inputTable[3]
+    if functionArgs[1] == "number" then
+        return { success = true, value = 42.5, errorMessage = "" }
+    elseif functionArgs[1] == "bool" then
+        return { success = true, value = true, errorMessage = "" }
+    elseif functionArgs[1] == "empty" then
+        return { success = true, value = {}, errorMessage = "" }
+    elseif functionArgs[1] == "function" then
+        return { success = true, value = print, errorMessage = "" }
+    end
+    return { success = true, value = "text", errorMessage = "" }
+end
+`
+
+func registerAddressTestHandler(t *testing.T, functionName string) {
+	t.Helper()
+
+	if err := RegisterGoPlaceholderValueFunction(functionName, func(input GoPlaceholderInput) (PlaceholderValue, error) {
+		return MapValue(map[string]PlaceholderValue{
+			"street": StringValue("Main Street 1"),
+			"zip":    NumberValue(12345),
+			"lines":  ListValue(StringValue("Main Street 1"), StringValue("Stockholm")),
+		}), nil
+	}); err != nil {
+		t.Fatalf("failed to register test handler: %v", err)
+	}
+	t.Cleanup(func() {
+		goPlaceholderFunctionsMutex.Lock()
+		delete(goPlaceholderFunctions, functionName)
+		goPlaceholderFunctionsMutex.Unlock()
+	})
+}
+
+func TestPlaceholderValue_ShouldFormatAndAccessFields(t *testing.T) {
+	address := MapValue(map[string]PlaceholderValue{
+		"zip":   NumberValue(12345),
+		"lines": ListValue(StringValue("Main Street 1"), BoolValue(true)),
+	})
+
+	testCases := []struct {
+		name          string
+		value         PlaceholderValue
+		fieldPath     []string
+		expectedValue string
+		expectedError string
+	}{
+		{name: "zero value", value: PlaceholderValue{}, expectedValue: ""},
+		{name: "number", value: NumberValue(0.1), expectedValue: "0.1"},
+		{name: "bool", value: BoolValue(false), expectedValue: "false"},
+		{name: "map as json", value: address, expectedValue: `{"lines":["Main Street 1",true],"zip":12345}`},
+		{name: "map field", value: address, fieldPath: []string{"zip"}, expectedValue: "12345"},
+		{name: "list position", value: address, fieldPath: []string{"lines", "2"}, expectedValue: "true"},
+		{name: "missing field", value: address, fieldPath: []string{"city"}, expectedError: "available fields: lines, zip"},
+		{name: "list position out of range", value: address, fieldPath: []string{"lines", "3"}, expectedError: "must be between 1 and 2"},
+		{name: "field of a scalar", value: address, fieldPath: []string{"zip", "code"}, expectedError: "of a number value"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := testCase.value.Field(testCase.fieldPath...)
+			t.Logf("Output [%s]\n  FieldPath: %v\n  Value: %q\n  Error: %v", testCase.name, testCase.fieldPath, value.Format(), err)
+			if testCase.expectedError != "" {
+				if err == nil || strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if value.Format() != testCase.expectedValue {
+				t.Fatalf("expected %q, got %q", testCase.expectedValue, value.Format())
+			}
+		})
+	}
+}
+
+func TestExecutePlaceholder_ShouldApplyFieldPathToGoValue(t *testing.T) {
+	registerAddressTestHandler(t, "Test_GoAddress")
+
+	testCases := []struct {
+		name          string
+		fieldPath     []string
+		expectedValue string
+		expectedKind  PlaceholderErrorKind
+	}{
+		{name: "whole value", expectedValue: `{"lines":["Main Street 1","Stockholm"],"street":"Main Street 1","zip":12345}`},
+		{name: "zip", fieldPath: []string{"zip"}, expectedValue: "12345"},
+		{name: "second line", fieldPath: []string{"lines", "2"}, expectedValue: "Stockholm"},
+		{name: "missing field", fieldPath: []string{"city"}, expectedKind: PlaceholderErrorKindFieldAccess},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			request := PlaceholderExecutionRequest{FunctionName: "Test_GoAddress", FieldPath: testCase.fieldPath}
+			logExecutionRequest(t, testCase.name, request)
+			value, err := ExecutePlaceholder(request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if testCase.expectedKind != "" {
+				if PlaceholderErrorKindOf(err) != testCase.expectedKind {
+					t.Fatalf("expected error kind %q, got: %v", testCase.expectedKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if value != testCase.expectedValue {
+				t.Fatalf("expected %q, got %q", testCase.expectedValue, value)
+			}
+		})
+	}
+
+	typedValue, err := ExecutePlaceholderValue(PlaceholderExecutionRequest{FunctionName: "Test_GoAddress", FieldPath: []string{"zip"}})
+	if err != nil || typedValue.Kind != PlaceholderValueKindNumber || typedValue.Number != 12345 {
+		t.Fatalf("expected typed number 12345, got %+v (%v)", typedValue, err)
+	}
+}
+
+func TestExecutePlaceholder_ShouldConvertLuaTypedValues(t *testing.T) {
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{{LuaScriptName: "valueTest", LuaScript: []byte(valueTestLuaScript)}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	testCases := []struct {
+		name          string
+		functionName  string
+		arguments     []string
+		fieldPath     []string
+		expectedValue string
+		expectedKind  PlaceholderErrorKind
+	}{
+		{name: "string", functionName: "Test_LuaScalar", arguments: []string{"string"}, expectedValue: "text"},
+		{name: "number", functionName: "Test_LuaScalar", arguments: []string{"number"}, expectedValue: "42.5"},
+		{name: "bool", functionName: "Test_LuaScalar", arguments: []string{"bool"}, expectedValue: "true"},
+		{name: "empty table", functionName: "Test_LuaScalar", arguments: []string{"empty"}, expectedValue: "[]"},
+		{name: "unsupported type", functionName: "Test_LuaScalar", arguments: []string{"function"}, expectedKind: PlaceholderErrorKindInvalidResponse},
+		{name: "table field", functionName: "Test_LuaAddress", fieldPath: []string{"zip"}, expectedValue: "12345"},
+		{name: "nested list", functionName: "Test_LuaAddress", fieldPath: []string{"lines", "2"}, expectedValue: "Stockholm"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			request := PlaceholderExecutionRequest{FunctionName: testCase.functionName, Arguments: testCase.arguments, FieldPath: testCase.fieldPath}
+			logExecutionRequest(t, testCase.name, request)
+			value, err := ExecutePlaceholder(request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+			if testCase.expectedKind != "" {
+				if PlaceholderErrorKindOf(err) != testCase.expectedKind {
+					t.Fatalf("expected error kind %q, got: %v", testCase.expectedKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if value != testCase.expectedValue {
+				t.Fatalf("expected %q, got %q", testCase.expectedValue, value)
+			}
+		})
+	}
+}
+
+func TestExecuteLuaScriptBasedOnPlaceholder_ShouldAcceptFieldPathElement(t *testing.T) {
+	registerAddressTestHandler(t, "Test_LegacyAddress")
+
+	inputParameterArray := []interface{}{
+		"{{Test.LegacyAddress().street}}", "Test_LegacyAddress", []interface{}{}, []interface{}{}, true, uint64(0), "", []interface{}{"street"},
+	}
+	logDispatcherInputMatrix(t, "legacy-field-path", inputParameterArray, "")
+	value := ExecuteLuaScriptBasedOnPlaceholder(inputParameterArray, "")
+	t.Logf("Output [legacy-field-path]\n  Value: %q", value)
+	if value != "Main Street 1" {
+		t.Fatalf("expected street from field path, got %q", value)
+	}
+
+	inputParameterArray[7] = []interface{}{1}
+	value = ExecuteLuaScriptBasedOnPlaceholder(inputParameterArray, "")
+	if strings.Contains(value, "field path elements must be strings") == false {
+		t.Fatalf("expected field path type error, got %q", value)
+	}
+}
@@ -2,6 +2,15 @@
 -- Usage: {{HappyLuaTime()}}
 -- Returns: "My name is Lua and the time is <HH:MM:SS>"
 
+-- Read by the engine at load time. Calls with arguments or array indexes are rejected before the function runs,
+-- the checks in the function remain for callers from Lua.
+HappyLuaTime_meta = {
+    description = "Greeting from Lua with the current time as HH:MM:SS.",
+    args = {},
+    array_indexes = "none",
+    examples = { "{{HappyLuaTime()}}" },
+}
+
 function HappyLuaTime(inputTable)
     local responseTable = {
         success = true,
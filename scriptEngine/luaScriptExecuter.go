@@ -1,175 +1,320 @@
 package scriptEngine
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"github.com/yuin/gopher-lua"
-	"hash/crc32"
-	"log"
+	"sort"
 	"strings"
 )
 
 // InitiateLuaScriptEngine
-// Initiate the Lua Script Engine
+// Initiate the Lua Script Engine with all standard libraries and without limits
 func InitiateLuaScriptEngine(luaScriptFiles []LuaScriptsStruct) (err error) {
+	return InitiateLuaScriptEngineWithOptions(luaScriptFiles, LuaEngineOptions{})
+}
+
+// InitiateLuaScriptEngineWithOptions
+// Initiate the Lua Script Engine with a sandbox profile, resource limits and pool size, see DefaultLuaSandboxOptions
+func InitiateLuaScriptEngineWithOptions(luaScriptFiles []LuaScriptsStruct, luaEngineOptions LuaEngineOptions) (err error) {
+	if err = luaEngineOptions.validate(); err != nil {
+		return err
+	}
+
+	// Fail fast: the engine is not started when a script fails to compile or run
+	newLuaEngine, err := buildLuaStatePool(luaScriptFiles, luaEngineOptions, true)
+	if err != nil {
+		newLuaEngine.close()
+		return err
+	}
+
+	swapLuaEngine(newLuaEngine)
+
+	return nil
+}
+
+// buildLuaStatePool creates a pool where every state has the Fenix scripts and the domain scripts loaded.
+// When scripts fail, the pool is returned with one state and a LuaScriptLoadErrors with all failures.
+// When domain scripts fail integrity verification, no script is run and the pool has no states.
+func buildLuaStatePool(domainLuaScriptFiles []LuaScriptsStruct, luaEngineOptions LuaEngineOptions, listLoadedLibraries bool) (*luaStatePool, error) {
 
 	// Load Fenix Lua Script files
 	var fenixLuaScripts []LuaScriptsStruct
 	fenixLuaScripts = loadFenixLuaScripts()
 
-	// Concatenate Fenix Lua scripts with Domain supported Lua scripts
-	for _, fenixLuaScript := range fenixLuaScripts {
-		luaScriptFiles = append(luaScriptFiles, fenixLuaScript)
+	// Concatenate Fenix Lua scripts with Domain supported Lua scripts.
+	// Domain scripts are loaded last so they can override Fenix functions with the same name.
+	luaScriptFiles := append(fenixLuaScripts, domainLuaScriptFiles...)
+
+	// Verify the domain scripts before any script runs
+	verifiedScriptReports, integrityErrors := verifyLuaScripts(luaScriptFiles, len(fenixLuaScripts), luaEngineOptions)
+	if len(integrityErrors) > 0 {
+		for _, integrityError := range integrityErrors {
+			scriptEngineLogger().Error("Lua script refused", logKeyScript, integrityError.ScriptName, "error", integrityError.Message)
+		}
+		return &luaStatePool{}, integrityErrors
 	}
 
+	// Initiate one Lua state per pool slot, all with the same libraries and scripts
+	poolSize := luaEngineOptions.PoolSize
+	if poolSize < 1 {
+		poolSize = 1
+	}
+	// The first state reports the load errors and defined functions, which are the same for all states
+	firstLuaState, scriptLoadReports, loadErrors := newInitiatedLuaState(luaScriptFiles, luaEngineOptions, listLoadedLibraries)
+	for scriptIndex := range scriptLoadReports {
+		scriptLoadReports[scriptIndex].SHA256 = verifiedScriptReports[scriptIndex].SHA256
+		scriptLoadReports[scriptIndex].Verification = verifiedScriptReports[scriptIndex].Verification
+		scriptLoadReports[scriptIndex].SignedBy = verifiedScriptReports[scriptIndex].SignedBy
+	}
+	loadErrors = withLuaModuleDependencyErrors(loadErrors, checkLuaModuleDependencies(firstLuaState, luaScriptFiles, luaEngineOptions))
+	functionMetadata, metadataErrors := readLuaPlaceholderMetadata(firstLuaState, scriptLoadReports)
+	loadErrors = append(loadErrors, metadataErrors...)
+	luaStates := []*lua.LState{firstLuaState}
+	if len(loadErrors) == 0 {
+		for stateIndex := 1; stateIndex < poolSize; stateIndex++ {
+			luaState, _, _ := newInitiatedLuaState(luaScriptFiles, luaEngineOptions, false)
+			luaStates = append(luaStates, luaState)
+		}
+	}
+
+	// Now list all the functions in the global environment
+	functionNames := map[string]bool{}
+	for functionName := range luaGlobalFunctions(firstLuaState) {
+		functionNames[functionName] = true
+	}
+	if listLoadedLibraries == true {
+		for _, scriptLoadReport := range scriptLoadReports {
+			scriptEngineLogger().Debug("Lua script loaded", logKeyScript, scriptLoadReport.ScriptName,
+				"sha256", scriptLoadReport.SHA256, "verification", scriptLoadReport.Verification,
+				"definedFunctions", scriptLoadReport.DefinedFunctions)
+		}
+	}
+	for _, loadError := range loadErrors {
+		scriptEngineLogger().Error("Lua script failed to load",
+			logKeyScript, loadError.ScriptName, "phase", loadError.Phase, "line", loadError.Line, "error", loadError.Message)
+	}
+
+	newLuaEngine := newLuaStatePool(luaStates, functionNames, luaEngineOptions.InstructionBudget)
+	newLuaEngine.luaScriptFiles = luaScriptFiles
+	newLuaEngine.domainLuaScriptFiles = domainLuaScriptFiles
+	newLuaEngine.options = luaEngineOptions
+	newLuaEngine.scriptLoadReports = scriptLoadReports
+	newLuaEngine.functionMetadata = functionMetadata
+
+	if len(loadErrors) > 0 {
+		return newLuaEngine, loadErrors
+	}
+
+	return newLuaEngine, nil
+}
+
+// swapLuaEngine replaces the engine after running calls are done, and closes the previous states.
+func swapLuaEngine(newLuaEngine *luaStatePool) {
+	luaEngineMutex.Lock()
+	defer luaEngineMutex.Unlock()
+
+	if luaEngine != nil {
+		luaEngine.close()
+	}
+	luaEngine = newLuaEngine
+
 	// Save all script into one byte array
-	luaScriptFilesAsByteArray = luaScriptFiles
+	luaScriptFilesAsByteArray = newLuaEngine.luaScriptFiles
 
-	// Initiate Gopher-Lua Script Engine state variables
-	luaState = lua.NewState()
+	// Compiled scripts that the new engine doesn't use are not needed by later reloads either
+	retainCompiledLuaScripts(newLuaEngine.luaScriptFiles)
+}
 
-	// Load standard libraries
-	luaState.OpenLibs()
+// newInitiatedLuaState creates one Lua state with libraries, the 'date' and 'fenix' modules, the module scripts
+// registered and all other scripts loaded.
+// Loading continues after a failing script, so all failures are returned, together with the functions each script defined.
+func newInitiatedLuaState(luaScriptFiles []LuaScriptsStruct, luaEngineOptions LuaEngineOptions, listLoadedLibraries bool) (
+	luaState *lua.LState, scriptLoadReports []LuaScriptLoadReport, loadErrors LuaScriptLoadErrors) {
 
-	// Preload the 'date' module
-	preloadLuaModule(luaState, "date", date)
+	// Initiate Gopher-Lua Script Engine state variables
+	luaState = lua.NewState(luaEngineOptions.luaStateOptions())
 
-	// List preloaded libraries
-	listLibraries(luaState)
+	// Load standard libraries, or only the allowed ones in the sandbox
+	openLuaLibraries(luaState, luaEngineOptions)
 
-	fmt.Println("Lua-libs after loading Standard Libs")
+	// Seeded random values are the same on every run
+	replaceLuaMathRandom(luaState)
 
-	// Remove or stub unsafe functions
-	//luaState.SetGlobal("io", lua.LNil) // Remove the 'io' library
-	//luaState.SetGlobal("os", lua.LNil) // Remove the 'os' library
-	//luaState.SetGlobal("dofile", lua.LNil)   // Remove the 'dofile' function
-	//luaState.SetGlobal("loadfile", lua.LNil) // Remove the 'loadfile' function
+	// Lua dates follow the clock and time zone of the placeholder call, also in the 'date' module
+	replaceLuaOsTime(luaState)
 
-	// Load the Lua scripts
-	for _, luaScriptFile := range luaScriptFilesAsByteArray {
-		//err = luaState.Load(string(luaScriptFileAsByteArray))
-		//_, err = luaState.Load(bytes.NewReader(luaScriptFileAsByteArray), "script")
-		loadAndExecuteScript(luaState, luaScriptFile)
-		if err != nil {
-			log.Fatal(err)
-		}
+	// Preload the 'date' module
+	if loadError := registerLuaModule(luaState, LuaScriptsStruct{LuaScriptName: "date", LuaScript: date, IsModule: true}); loadError != nil {
+		loadErrors = append(loadErrors, loadError)
 	}
 
-	// Now list all the functions in the global environment
-	global := luaState.Get(lua.GlobalsIndex) // Access the global table
-	if tbl, ok := global.(*lua.LTable); ok {
-		tbl.ForEach(func(key, value lua.LValue) {
-			if _, ok := value.(*lua.LFunction); ok {
-				fmt.Printf("Function: %s\n", key.String())
-			}
-		})
+	// Preload the Go backed 'fenix' module
+	preloadFenixLuaModule(luaState)
+
+	if listLoadedLibraries == true {
+		// List preloaded libraries
+		listLibraries(luaState)
 	}
 
-	// Replace the default 'print' with our custom function
+	// Replace the default 'print', so output is captured per call and forwarded to the Lua output logger
 	luaState.SetGlobal("print", luaState.NewFunction(customPrint))
 
-	// Run a Lua script
-	if err := luaState.DoString(`print("Hello", "world", 123)`); err != nil {
-		fmt.Println("Error running Lua script:", err)
+	// Register the modules first, so scripts can require them whatever the order of the scripts.
+	// A name that is already taken is a dependency error, see checkLuaModuleDependencies, and keeps its first module.
+	registeredModuleNames := map[string]bool{}
+	for _, luaScriptFile := range luaScriptFiles {
+		moduleName := luaScriptFile.luaModuleName()
+		if luaScriptFile.IsModule == false || registeredModuleNames[moduleName] == true || isLuaBuiltInModule(moduleName) == true {
+			continue
+		}
+		registeredModuleNames[moduleName] = true
+		if loadError := registerLuaModule(luaState, luaScriptFile); loadError != nil {
+			loadErrors = append(loadErrors, loadError)
+		}
 	}
 
-	return err
-}
+	// Load the Lua scripts, modules only run when they are required
+	globalFunctions := luaGlobalFunctions(luaState)
+	for _, luaScriptFile := range luaScriptFiles {
+		if luaScriptFile.IsModule == true {
+			scriptLoadReports = append(scriptLoadReports, LuaScriptLoadReport{
+				ScriptName: luaScriptFile.LuaScriptName,
+				ModuleName: luaScriptFile.luaModuleName(),
+			})
+			continue
+		}
+		if loadError := loadAndExecuteScript(luaState, luaScriptFile, luaEngineOptions.InstructionBudget); loadError != nil {
+			loadErrors = append(loadErrors, loadError)
+		}
 
-// customPrint replaces the default Lua print function to capture output in Go.
-func customPrint(L *lua.LState) int {
-	top := L.GetTop()
-	parts := make([]string, top)
-	for i := 1; i <= top; i++ {
-		parts[i-1] = L.ToStringMeta(L.Get(i)).String()
+		globalFunctionsAfterScript := luaGlobalFunctions(luaState)
+		scriptLoadReports = append(scriptLoadReports, LuaScriptLoadReport{
+			ScriptName:       luaScriptFile.LuaScriptName,
+			DefinedFunctions: definedLuaFunctions(globalFunctions, globalFunctionsAfterScript),
+		})
+		globalFunctions = globalFunctionsAfterScript
 	}
-	fmt.Println(strings.Join(parts, "\t"))
-	return 0 // Number of results
+
+	return luaState, scriptLoadReports, loadErrors
 }
 
 // CloseDownLuaScriptEngine
-// Close down the Lua Script Engine in a correct way
+// Close down the Lua Script Engine in a correct way, after running calls are done
 func CloseDownLuaScriptEngine() {
-	luaState.Close()
+	luaEngineMutex.Lock()
+	defer luaEngineMutex.Unlock()
+
+	if luaEngine == nil {
+		return
+	}
+
+	luaEngine.close()
+	luaEngine = nil
 }
 
+// listLibraries logs the modules that can be loaded with require.
 func listLibraries(L *lua.LState) {
 	// Directly access the global 'package' table
 	packageTable := L.GetGlobal("package")
 	if packageTable.Type() == lua.LTNil {
-		fmt.Println("No 'package' global found.")
+		scriptEngineLogger().Debug("no 'package' global found in Lua state")
 		return
 	}
 
 	// Access 'preload' table inside 'package'
 	preloadTable := L.GetField(packageTable, "preload")
 	if tbl, ok := preloadTable.(*lua.LTable); ok {
-		fmt.Println("Preloaded libraries:")
+		var preloadedModules []string
 		tbl.ForEach(func(key lua.LValue, value lua.LValue) {
-			fmt.Println(key.String())
+			preloadedModules = append(preloadedModules, key.String())
 		})
+		sort.Strings(preloadedModules)
+		scriptEngineLogger().Debug("Lua libraries loaded", "preloadedModules", preloadedModules)
 	} else {
-		fmt.Println("No preloaded libraries found or 'preload' is not a table.")
+		scriptEngineLogger().Debug("no preloaded Lua libraries found or 'preload' is not a table")
 	}
 }
 
 // ExecuteLuaScriptBasedOnPlaceholder
-// Execute a specific Lua function
+// Execute a specific placeholder function based on the legacy positional input format
+// [placeholder, functionName, arrayIndexes, arguments, useEntropy, extraEntropy].
+// The input is validated before use and any failure is returned as the response text.
 func ExecuteLuaScriptBasedOnPlaceholder(inputParameterArray []interface{}, testCaseExecutionUuid string) (responseValue string) {
 
-	var err error
-	var luaFunctionToCall string
-	var addExtraEntropyValue uint64
-	var useEntropyFromTestCaseExecutionUuid bool
-	var entropyToUse uint64
-
-	luaFunctionToCall = inputParameterArray[1].(string)
-	useEntropyFromTestCaseExecutionUuid = inputParameterArray[4].(bool)
-	addExtraEntropyValue = inputParameterArray[5].(uint64)
-
-	// Go handlers are evaluated first so migrated functions no longer depend on Lua VM.
-	// If no Go handler is registered we continue with the legacy Lua execution path.
-	responseValue, wasHandledByGo, err := executeGoPlaceholderFunction(inputParameterArray, testCaseExecutionUuid)
-	if wasHandledByGo == true {
-		if err != nil {
-			return err.Error()
-		}
-		return responseValue
+	request, err := parseLegacyPlaceholderRequest(inputParameterArray, testCaseExecutionUuid)
+	if err != nil {
+		return err.Error()
 	}
 
-	// Decide how much entropy to use
-	if useEntropyFromTestCaseExecutionUuid == true {
+	responseValue, err = ExecutePlaceholder(request)
+	if err != nil {
+		return err.Error()
+	}
 
-		// Create entropy form TestCaseExecutionUuid by converting string into 32-bit hash
-		var entropyBasedOnTestCaseExecutionUuid uint32
-		entropyBasedOnTestCaseExecutionUuid = crc32.ChecksumIEEE([]byte(testCaseExecutionUuid))
+	return responseValue
+}
 
-		// Create final entropy
-		entropyToUse = uint64(entropyBasedOnTestCaseExecutionUuid) + addExtraEntropyValue
+// executeLuaPlaceholderFunction executes a placeholder function implemented in Lua.
+func executeLuaPlaceholderFunction(input GoPlaceholderInput) (responseValue PlaceholderValue, err error) {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
 
-	} else {
-		entropyToUse = addExtraEntropyValue
+	if luaEngine == nil {
+		return PlaceholderValue{}, newPlaceholderExecutionError(
+			PlaceholderErrorKindEngine,
+			input.FunctionName,
+			fmt.Errorf("Lua script engine is not initiated, can't execute placeholder function '%s'", input.FunctionName))
+	}
+
+	// Every caller gets the checks of the declared metadata, also shadow mode that calls this directly
+	if metadata, exists := luaEngine.functionMetadata[input.FunctionName]; exists == true && metadata.ValidatedByHandler == false {
+		if err = validatePlaceholderInput(metadata, input); err != nil {
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidInput, input.FunctionName, err)
+		}
+	}
+
+	// Check out a Lua state for this call
+	luaState := luaEngine.checkOut()
+	defer luaEngine.checkIn(luaState)
+
+	// The 'fenix' module reads entropy, clock and TestData of this call
+	setFenixLuaCallInput(luaState, &input)
+	defer setFenixLuaCallInput(luaState, nil)
+
+	if _, ok := luaState.GetGlobal(input.FunctionName).(*lua.LFunction); ok == false {
+		return PlaceholderValue{}, newPlaceholderExecutionError(
+			PlaceholderErrorKindFunctionNotFound,
+			input.FunctionName,
+			fmt.Errorf("placeholder function '%s' is neither a registered Go function nor a Lua function", input.FunctionName))
 	}
 
 	// Lua functions expect entropy at position 4 as a nested table: {useExecutionUUID, entropyValue}.
 	entropyTable := luaState.NewTable() // Instantiate the Lua table
 
 	// Append a boolean value
-	luaState.SetTable(entropyTable, lua.LNumber(1), lua.LBool(useEntropyFromTestCaseExecutionUuid))
+	luaState.SetTable(entropyTable, lua.LNumber(1), lua.LBool(input.UseEntropyFromExecutionUUID))
 
 	// Append an integer value
-	luaState.SetTable(entropyTable, lua.LNumber(2), lua.LNumber(entropyToUse))
+	luaState.SetTable(entropyTable, lua.LNumber(2), lua.LNumber(input.Entropy))
+
+	var arrayIndexes []interface{}
+	for _, arrayIndex := range input.ArrayIndexes {
+		arrayIndexes = append(arrayIndexes, arrayIndex)
+	}
+
+	var functionArguments []interface{}
+	for _, functionArgument := range input.Arguments {
+		functionArguments = append(functionArguments, functionArgument)
+	}
 
 	var goArrayToBeConvertedIntoLuaTable []interface{}
-	goArrayToBeConvertedIntoLuaTable = inputParameterArray[1:4]
+	goArrayToBeConvertedIntoLuaTable = []interface{}{input.FunctionName, arrayIndexes, functionArguments}
 
 	// Build Lua input table in legacy format: {functionName, arrayIndexes, arguments, entropyTable}.
 	var luaInputTable *lua.LTable
 	luaInputTable = convertToLuaTableRecursively(luaState, goArrayToBeConvertedIntoLuaTable)
 
-	// Append 'entropyTable' to 'luaInputTable'
-
 	// Get number of elements in table
 	var numberOfElementsInTable int
 	numberOfElementsInTable = luaInputTable.Len()
@@ -177,58 +322,33 @@ func ExecuteLuaScriptBasedOnPlaceholder(inputParameterArray []interface{}, testC
 	// Append an entropy table to 'luaInputTable'
 	luaState.SetTable(luaInputTable, lua.LNumber(numberOfElementsInTable+1), entropyTable)
 
-	/*
-		// Add the 'entropyBasedOnTestCaseExecutionUuid' to the entropy position, which is position 4
-		originalValue := luaState.GetField(luaInputTable, "4")
-		fmt.Println("Original value at position 4:", originalValue)
+	// Limit the number of instructions for this call
+	if luaEngine.instructionBudget > 0 {
+		budgetContext := newLuaInstructionBudgetContext(luaEngine.instructionBudget)
+		luaState.SetContext(budgetContext)
+		defer luaState.RemoveContext()
 
-
-		// Add the Go value to the Lua value at position 4
-		lv, ok := originalValue.(lua.LNumber)
-		if ok == true {
-			newValue := lua.LNumber(entropyBasedOnTestCaseExecutionUuid) + lv
-			luaState.SetField(luaInputTable, "4", newValue) // Set the new value at position 4
-		} else {
-			// Original value is not a number
-			err = errors.New(fmt.Sprintf("original value, '%s' in position 4 is not a number", originalValue))
-
-			return err.Error()
-		}
-
-
-
-		// Access and modify the number in the nested table
-		nestedTable, ok := originalValue.(*lua.LTable)
-		if ok == true {
-			nestedValue := luaState.GetField(nestedTable, "1")
-			lv, ok := nestedValue.(lua.LNumber)
-			if ok == true {
-				newValue := lua.LNumber(entropyBasedOnTestCaseExecutionUuid) + lv
-				luaState.SetField(nestedTable, "1", newValue) // Update the number in the nested table
-			} else {
-				// Original value is not a number
-				err = errors.New(fmt.Sprintf("original value, '%s' in position 1 in table is not a number", nestedValue))
-
-				return err.Error()
-			}
-		} else {
-			// Original value is not a lua table
-			err = errors.New(fmt.Sprintf("original value, '%s' in position 4 is not a table", originalValue))
-			return err.Error()
+		responseValue, err = callPlaceholderFunctionWithInputTable(luaState, input.FunctionName, luaInputTable)
+		if err != nil && budgetContext.exceeded() == true {
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindResourceLimit, input.FunctionName, budgetContext.Err())
 		}
-	*/
+		return responseValue, err
+	}
 
 	// Call lua function based on Placeholder
-	responseValue, err = callPlaceholderFunctionWithInputTable(luaState, luaFunctionToCall, luaInputTable)
+	return callPlaceholderFunctionWithInputTable(luaState, input.FunctionName, luaInputTable)
+}
 
-	// If there is an error then create error message with input table as response
-	if err != nil {
+// luaPlaceholderFunctionExists returns true when the loaded Lua scripts define a global function with the name.
+func luaPlaceholderFunctionExists(functionName string) bool {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
 
-		responseValue = err.Error() //+ "\n" + printLuaTable(luaState, luaInputTable, "-")
-		return responseValue
+	if luaEngine == nil {
+		return false
 	}
 
-	return responseValue
+	return luaEngine.functionNames[functionName]
 }
 
 // printLuaTable recursively prints a Lua table and returns the result as a string
@@ -265,84 +385,35 @@ func convertToLuaTableRecursively(tempLuaState *lua.LState, goSlice []interface{
 	return luaTable
 }
 
-// loadAndExecuteScript compiles and runs one embedded Lua script in the shared Lua state.
-func loadAndExecuteScript(L *lua.LState, luaScript LuaScriptsStruct) (err error) {
+// loadAndExecuteScript runs one Lua script in a Lua state, compiling it only if no earlier state did.
+// The chunk is named after the script, so errors and stack traces point at the script and line.
+// Running the script uses at most the instruction budget of one placeholder call.
+func loadAndExecuteScript(L *lua.LState, luaScript LuaScriptsStruct, instructionBudget uint64) *LuaScriptLoadError {
 
-	/*
-		loader := func(L *lua.LState) int {
-			if err := L.DoString(string(luaScript.LuaScript)); err != nil {
-				L.RaiseError("Error loading date module: %v", err)
-				return 0
-			}
-			return 1
-		}
-		L.PreloadModule(luaScript.LuaScriptName, loader)
-
-	*/
+	scriptEngineLogger().Debug("loading Lua script", logKeyScript, luaScript.LuaScriptName)
 
-	fmt.Println(fmt.Sprintf("Load scrip: '%s'", luaScript.LuaScriptName))
-
-	if fn, err := L.Load(bytes.NewReader(luaScript.LuaScript), "luaScript.LuaScriptName"); err != nil {
-		return err
-	} else {
-		L.Push(fn)
-		return L.PCall(0, lua.MultRet, nil)
+	fn, err := loadCompiledLuaScript(L, luaScript)
+	if err != nil {
+		return newLuaScriptCompileError(luaScript.LuaScriptName, err)
 	}
 
-	return err
-}
-
-/*
-// preloadLuaModule registers a module loader in package.preload for `require(...)` usage.
-func preloadLuaModule(L *lua.LState, moduleName string, moduleCode []byte) {
-	// Get the 'package.preload' table
-	preload := L.GetField(L.GetField(L.Get(lua.EnvironIndex), "package"), "preload").(*lua.LTable)
-	// Create a loader function
-	loader := L.NewFunction(func(L *lua.LState) int {
-		// Load and execute the module code
-		if err := L.DoString(string(moduleCode)); err != nil {
-			L.RaiseError("Error loading module %s: %v", moduleName, err)
-		}
-		// Return 0 since module code should set values in the global scope or return them
-		return 0
-	})
-	// Set the loader function in 'package.preload'
-	L.SetField(preload, moduleName, loader)
-}
-*/
+	// A script that never ends fails the load instead of blocking it
+	if instructionBudget > 0 {
+		L.SetContext(newLuaInstructionBudgetContext(instructionBudget))
+		defer L.RemoveContext()
+	}
 
-func preloadLuaModule(L *lua.LState, moduleName string, moduleCode []byte) {
-	// Get the 'package.preload' table
-	packageTbl := L.GetGlobal("package").(*lua.LTable)
-	preload := L.GetField(packageTbl, "preload").(*lua.LTable)
+	L.Push(fn)
+	if err = L.PCall(0, 0, nil); err != nil {
+		return newLuaScriptRunError(luaScript.LuaScriptName, err)
+	}
 
-	// Create a loader function
-	loader := L.NewFunction(func(L *lua.LState) int {
-		// Compile the module code into a function
-		fn, err := L.LoadString(string(moduleCode))
-		if err != nil {
-			L.RaiseError("Error compiling module %s: %v", moduleName, err)
-			return 0
-		}
-		// Call the compiled function to execute the module code
-		if err := L.CallByParam(lua.P{
-			Fn:      fn,
-			NRet:    1,    // Expecting the module to return one value
-			Protect: true, // Run in protected mode
-		}); err != nil {
-			L.RaiseError("Error running module %s: %v", moduleName, err)
-			return 0
-		}
-		// The module's return value is now on top of the stack
-		return 1 // Indicate that one value is being returned
-	})
-	// Set the loader function in 'package.preload'
-	L.SetField(preload, moduleName, loader)
+	return nil
 }
 
 // callPlaceholderFunctionWithInputTable executes one Lua placeholder function and validates
-// the expected response contract: {success:boolean, value:string, errorMessage:string}.
-func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, placeholderInputTable *lua.LTable) (luaFunctionResponse string, err error) {
+// the expected response contract: {success:boolean, value:string|number|boolean|table, errorMessage:string}.
+func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, placeholderInputTable *lua.LTable) (luaFunctionResponse PlaceholderValue, err error) {
 	//L.GetGlobal(funcName)
 	//L.Push(placeholderInputTable)
 	//err = L.PCall(1, 1, nil)
@@ -356,7 +427,7 @@ func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, place
 		placeholderInputTable)
 
 	if err != nil {
-		return err.Error(), err
+		return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindLuaRuntime, funcName, err)
 	}
 
 	// Extract the response
@@ -370,7 +441,7 @@ func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, place
 		// Extract response
 		var success, value, errorMessage lua.LValue
 		var successAsBool bool
-		var valueString string
+		var placeholderValue PlaceholderValue
 		var errorMessageAsString string
 
 		success = luaResponseTable.RawGetString("success")
@@ -381,27 +452,25 @@ func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, place
 		if success.Type() != lua.LTBool {
 			err = errors.New(fmt.Sprintf("In response from placeholder function: '%s' the responseTable.success is not of type Boolean. Instead the type seems to be a '%s'", funcName, value.Type().String()))
 
-			return "", err
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidResponse, funcName, err)
 		} else {
 
 			successAsBool = lua.LVAsBool(success)
 		}
 
-		// Check that 'value' of type string, and if so then convert into a string
-		if value.Type() != lua.LTString {
-			err = errors.New(fmt.Sprintf("In response from placeholder function: '%s' the responseTable.value is not of type String. Instead the type seems to be a '%s'", funcName, value.Type().String()))
-
-			return "", err
-		} else {
+		// Check that 'value' is a string, number, boolean or table, and if so then convert into a placeholder value
+		placeholderValue, err = luaValueToPlaceholderValue(value)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("In response from placeholder function: '%s' the responseTable.value can't be used. %s", funcName, err.Error()))
 
-			valueString = lua.LVAsString(value)
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidResponse, funcName, err)
 		}
 
 		// Check that 'errorMessage' of type string, and if so then convert into a string
 		if errorMessage.Type() != lua.LTString {
 			err = errors.New(fmt.Sprintf("In response from placeholder function: '%s' the responseTable.errorMessage is not of type String. Instead the type seems to be a '%s'", funcName, value.Type().String()))
 
-			return "", err
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidResponse, funcName, err)
 		} else {
 
 			errorMessageAsString = lua.LVAsString(errorMessage)
@@ -409,20 +478,94 @@ func callPlaceholderFunctionWithInputTable(L *lua.LState, funcName string, place
 
 		// Check if we got any error message back
 		if len(errorMessageAsString) > 0 {
-			return "", errors.New(errorMessageAsString)
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindHandler, funcName, errors.New(errorMessageAsString))
 		}
 
 		// Check if we didn't get a OK response and the errorMessage is empty
 		if len(errorMessageAsString) == 0 && successAsBool == false {
-			return "", errors.New(fmt.Sprintf("'errorMessage' from function '%s' is empty but responseTable.success is 'false'. This shouldn't happen", funcName))
+			return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidResponse, funcName, errors.New(fmt.Sprintf("'errorMessage' from function '%s' is empty but responseTable.success is 'false'. This shouldn't happen", funcName)))
 		}
 
 		// Return the response value from Lua
-		return valueString, nil
+		return placeholderValue, nil
 
 	} else {
 		err = errors.New(fmt.Sprintf("Expected a table, but didn't get one as a response from the Lua execution for Placeholder function: '%s'", funcName))
-		return "", err
+		return PlaceholderValue{}, newPlaceholderExecutionError(PlaceholderErrorKindInvalidResponse, funcName, err)
+	}
+
+}
+
+// maxLuaPlaceholderValueDepth bounds table nesting, which also stops self referencing tables.
+const maxLuaPlaceholderValueDepth = 32
+
+// luaValueToPlaceholderValue converts a Lua string, number, boolean or table into a placeholder value.
+// Tables with only the keys 1..n, and empty tables, become lists. Other tables become maps with string keys.
+func luaValueToPlaceholderValue(value lua.LValue) (PlaceholderValue, error) {
+	return luaValueToPlaceholderValueAtDepth(value, 0)
+}
+
+// luaValueToPlaceholderValueAtDepth converts one value at a given table nesting depth.
+func luaValueToPlaceholderValueAtDepth(value lua.LValue, depth int) (PlaceholderValue, error) {
+	switch typedValue := value.(type) {
+	case lua.LString:
+		return StringValue(string(typedValue)), nil
+	case lua.LNumber:
+		return NumberValue(float64(typedValue)), nil
+	case lua.LBool:
+		return BoolValue(bool(typedValue)), nil
+	case *lua.LTable:
+		if depth >= maxLuaPlaceholderValueDepth {
+			return PlaceholderValue{}, fmt.Errorf("tables are nested deeper than %d levels", maxLuaPlaceholderValueDepth)
+		}
+		return luaTableToPlaceholderValue(typedValue, depth+1)
+	default:
+		return PlaceholderValue{}, fmt.Errorf("values of type '%s' are not supported, use string, number, boolean or table", value.Type().String())
+	}
+}
+
+// luaTableToPlaceholderValue converts a Lua table into a list or map placeholder value.
+func luaTableToPlaceholderValue(table *lua.LTable, depth int) (PlaceholderValue, error) {
+	var numberOfKeys int
+	table.ForEach(func(lua.LValue, lua.LValue) {
+		numberOfKeys++
+	})
+
+	if numberOfKeys == table.Len() {
+		listValues := make([]PlaceholderValue, 0, numberOfKeys)
+		for position := 1; position <= numberOfKeys; position++ {
+			itemValue, err := luaValueToPlaceholderValueAtDepth(table.RawGetInt(position), depth)
+			if err != nil {
+				return PlaceholderValue{}, fmt.Errorf("list position %d: %w", position, err)
+			}
+			listValues = append(listValues, itemValue)
+		}
+
+		return ListValue(listValues...), nil
+	}
+
+	mapValues := make(map[string]PlaceholderValue, numberOfKeys)
+	var err error
+	table.ForEach(func(key lua.LValue, item lua.LValue) {
+		if err != nil {
+			return
+		}
+		keyString, ok := key.(lua.LString)
+		if ok == false {
+			err = fmt.Errorf("table key '%s' of type '%s' is not a string", key.String(), key.Type().String())
+			return
+		}
+		var itemValue PlaceholderValue
+		itemValue, err = luaValueToPlaceholderValueAtDepth(item, depth)
+		if err != nil {
+			err = fmt.Errorf("field '%s': %w", string(keyString), err)
+			return
+		}
+		mapValues[string(keyString)] = itemValue
+	})
+	if err != nil {
+		return PlaceholderValue{}, err
 	}
 
+	return MapValue(mapValues), nil
 }
@@ -0,0 +1,95 @@
+package scriptEngine
+
+import (
+	"bytes"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// compiledLuaScript is a script compiled once and shared by every Lua state that loads it. gopher-lua function
+// prototypes are read only, so all states can run the same prototype at the same time.
+type compiledLuaScript struct {
+	proto *lua.FunctionProto
+	// Require calls with a literal module name, found while the script was parsed.
+	luaRequires []luaRequire
+}
+
+// compiledLuaScriptKey identifies a compiled script by chunk name and content, so a changed script is compiled again.
+type compiledLuaScriptKey struct {
+	chunkName    string
+	scriptSHA256 string
+}
+
+var (
+	compiledLuaScriptsMutex sync.Mutex
+	// Compiled scripts of the Fenix scripts, the 'date' module and the domain scripts of the latest engine.
+	compiledLuaScripts = map[compiledLuaScriptKey]*compiledLuaScript{}
+)
+
+// compileLuaScript returns the compiled script, and only parses and compiles it the first time.
+// Errors are returned like L.Load returns them, so newLuaScriptCompileError can read the line.
+func compileLuaScript(luaScript LuaScriptsStruct) (*compiledLuaScript, error) {
+	key := compiledLuaScriptKey{chunkName: luaScript.LuaScriptName, scriptSHA256: luaScriptSHA256(luaScript.LuaScript)}
+
+	compiledLuaScriptsMutex.Lock()
+	compiledScript, exists := compiledLuaScripts[key]
+	compiledLuaScriptsMutex.Unlock()
+	if exists == true {
+		return compiledScript, nil
+	}
+
+	chunk, err := parse.Parse(bytes.NewReader(luaScript.LuaScript), luaScript.LuaScriptName)
+	if err != nil {
+		return nil, &lua.ApiError{Type: lua.ApiErrorSyntax, Object: lua.LString(err.Error()), Cause: err}
+	}
+	compiledScript = &compiledLuaScript{}
+	visitLuaStatements(chunk, true, &compiledScript.luaRequires)
+	if compiledScript.proto, err = lua.Compile(chunk, luaScript.LuaScriptName); err != nil {
+		return nil, &lua.ApiError{Type: lua.ApiErrorSyntax, Object: lua.LString(err.Error()), Cause: err}
+	}
+	scriptEngineLogger().Debug("compiled Lua script", logKeyScript, luaScript.LuaScriptName)
+
+	compiledLuaScriptsMutex.Lock()
+	defer compiledLuaScriptsMutex.Unlock()
+
+	// Another state may have compiled the script meanwhile, its prototype is kept
+	if existingScript, exists := compiledLuaScripts[key]; exists == true {
+		return existingScript, nil
+	}
+	compiledLuaScripts[key] = compiledScript
+
+	return compiledScript, nil
+}
+
+// loadCompiledLuaScript returns the main function of a script in a Lua state, like L.Load but without compiling
+// a script that was compiled before.
+func loadCompiledLuaScript(L *lua.LState, luaScript LuaScriptsStruct) (*lua.LFunction, error) {
+	compiledScript, err := compileLuaScript(luaScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return L.NewFunctionFromProto(compiledScript.proto), nil
+}
+
+// retainCompiledLuaScripts drops the compiled scripts that are not in the given scripts or the built-in modules,
+// so reloads of changed scripts don't keep the prototypes of every earlier version.
+func retainCompiledLuaScripts(luaScriptFiles []LuaScriptsStruct) {
+	retainedKeys := map[compiledLuaScriptKey]bool{
+		{chunkName: "date", scriptSHA256: luaScriptSHA256(date)}: true,
+	}
+	for _, luaScriptFile := range luaScriptFiles {
+		retainedKeys[compiledLuaScriptKey{chunkName: luaScriptFile.LuaScriptName, scriptSHA256: luaScriptSHA256(luaScriptFile.LuaScript)}] = true
+	}
+
+	compiledLuaScriptsMutex.Lock()
+	defer compiledLuaScriptsMutex.Unlock()
+
+	for key := range compiledLuaScripts {
+		if retainedKeys[key] == false {
+			delete(compiledLuaScripts, key)
+		}
+	}
+}
@@ -26,16 +26,22 @@ type LuaScriptsStruct struct {
 	LuaScriptName string
 	// Raw Lua source bytes.
 	LuaScript []byte
+	// The script is a module: it is registered in package.preload and only run when it is required.
+	IsModule bool
+	// Name the module is required with, the script name when empty.
+	ModuleName string
+	// Ed25519 signature over LuaScript, verified when LuaEngineOptions.Integrity is set.
+	Signature []byte
 }
 
 // loadFenixLuaScripts returns the default embedded script set bundled with the engine.
 func loadFenixLuaScripts() (fenixLuaScripts []LuaScriptsStruct) {
 
-	//fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{"date", date})
-	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{"fenix_ControlledUniqueId", fenix_ControlledUniqueId})
-	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{"fenix_RandomPositiveDecimalValue", fenix_RandomPositiveDecimalValue})
-	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{"fenix_TodayDateShift", fenix_TodayDateShift})
-	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{"happyLuaTime", happyLuaTime})
+	// 'date' is not in the list, it is a built-in module of every Lua state, see newInitiatedLuaState
+	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{LuaScriptName: "fenix_ControlledUniqueId", LuaScript: fenix_ControlledUniqueId})
+	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{LuaScriptName: "fenix_RandomPositiveDecimalValue", LuaScript: fenix_RandomPositiveDecimalValue})
+	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{LuaScriptName: "fenix_TodayDateShift", LuaScript: fenix_TodayDateShift})
+	fenixLuaScripts = append(fenixLuaScripts, LuaScriptsStruct{LuaScriptName: "happyLuaTime", LuaScript: happyLuaTime})
 
 	return fenixLuaScripts
 }
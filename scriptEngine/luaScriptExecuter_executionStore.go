@@ -0,0 +1,152 @@
+package scriptEngine
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// fenixLuaStoreTypeName is the metatable name of the store returned by fenix.store.
+const fenixLuaStoreTypeName = "fenix.store"
+
+// fenixLuaStoreMethods are the methods of fenix.store(), the same operations as PlaceholderExecutionStore.
+var fenixLuaStoreMethods = map[string]lua.LGFunction{
+	"get":           fenixLuaStoreGet,
+	"set":           fenixLuaStoreSet,
+	"set_if_absent": fenixLuaStoreSetIfAbsent,
+	"increment":     fenixLuaStoreIncrement,
+	"delete":        fenixLuaStoreDelete,
+	"keys":          fenixLuaStoreKeys,
+}
+
+// fenixLuaStore implements fenix.store(): the key/value store of the execution the call belongs to.
+// A Lua error is raised when the execution is not started.
+func fenixLuaStore(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "store")
+	if input.executionStore == nil {
+		L.RaiseError("fenix.store needs an execution started with StartPlaceholderExecution, execution '%s' is not started",
+			input.TestCaseExecutionUUID)
+	}
+
+	store := L.NewUserData()
+	store.Value = input.executionStore
+	L.SetMetatable(store, L.GetTypeMetatable(fenixLuaStoreTypeName))
+	L.Push(store)
+	return 1
+}
+
+// checkFenixLuaStore returns the store a method is called on.
+func checkFenixLuaStore(L *lua.LState) *PlaceholderExecutionStore {
+	if store, ok := L.CheckUserData(1).Value.(*PlaceholderExecutionStore); ok == true {
+		return store
+	}
+
+	L.ArgError(1, "fenix.store expected")
+	return nil
+}
+
+// checkFenixLuaStoreValue converts the value argument of a store method.
+func checkFenixLuaStoreValue(L *lua.LState, argumentIndex int) PlaceholderValue {
+	value, err := luaValueToPlaceholderValue(L.CheckAny(argumentIndex))
+	if err != nil {
+		L.ArgError(argumentIndex, err.Error())
+	}
+
+	return value
+}
+
+// fenixLuaStoreGet implements store:get(key), the value of the key or nil.
+func fenixLuaStoreGet(L *lua.LState) int {
+	value, exists := checkFenixLuaStore(L).Get(L.CheckString(2))
+	if exists == false {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	L.Push(placeholderValueToLuaValue(L, value))
+	return 1
+}
+
+// fenixLuaStoreSet implements store:set(key, value). Setting nil deletes the key.
+func fenixLuaStoreSet(L *lua.LState) int {
+	store := checkFenixLuaStore(L)
+	key := L.CheckString(2)
+
+	var err error
+	if L.Get(3) == lua.LNil {
+		err = store.Delete(key)
+	} else {
+		err = store.Set(key, checkFenixLuaStoreValue(L, 3))
+	}
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	return 0
+}
+
+// fenixLuaStoreSetIfAbsent implements store:set_if_absent(key, value), true when the value was stored.
+func fenixLuaStoreSetIfAbsent(L *lua.LState) int {
+	store := checkFenixLuaStore(L)
+	stored, err := store.SetIfAbsent(L.CheckString(2), checkFenixLuaStoreValue(L, 3))
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	L.Push(lua.LBool(stored))
+	return 1
+}
+
+// fenixLuaStoreIncrement implements store:increment(key [, delta]), the new number. The default delta is 1.
+func fenixLuaStoreIncrement(L *lua.LState) int {
+	store := checkFenixLuaStore(L)
+	number, err := store.Increment(L.CheckString(2), float64(L.OptNumber(3, 1)))
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	L.Push(lua.LNumber(number))
+	return 1
+}
+
+// fenixLuaStoreDelete implements store:delete(key).
+func fenixLuaStoreDelete(L *lua.LState) int {
+	if err := checkFenixLuaStore(L).Delete(L.CheckString(2)); err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	return 0
+}
+
+// fenixLuaStoreKeys implements store:keys(), the sorted keys with a value.
+func fenixLuaStoreKeys(L *lua.LState) int {
+	keys := L.NewTable()
+	for _, key := range checkFenixLuaStore(L).Keys() {
+		keys.Append(lua.LString(key))
+	}
+
+	L.Push(keys)
+	return 1
+}
+
+// placeholderValueToLuaValue converts a placeholder value into a Lua value. Lists become tables with the keys 1..n.
+func placeholderValueToLuaValue(L *lua.LState, value PlaceholderValue) lua.LValue {
+	switch value.kind() {
+	case PlaceholderValueKindNumber:
+		return lua.LNumber(value.Number)
+	case PlaceholderValueKindBool:
+		return lua.LBool(value.Bool)
+	case PlaceholderValueKindList:
+		table := L.CreateTable(len(value.List), 0)
+		for _, item := range value.List {
+			table.Append(placeholderValueToLuaValue(L, item))
+		}
+		return table
+	case PlaceholderValueKindMap:
+		table := L.CreateTable(0, len(value.Map))
+		for key, item := range value.Map {
+			table.RawSetString(key, placeholderValueToLuaValue(L, item))
+		}
+		return table
+	default:
+		return lua.LString(value.String)
+	}
+}
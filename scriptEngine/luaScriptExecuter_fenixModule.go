@@ -0,0 +1,240 @@
+package scriptEngine
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// fenixLuaModuleName is the name used with require("fenix").
+	fenixLuaModuleName = "fenix"
+	// fenixLuaCallInputKey is the registry key of the userdata that holds the input of the running placeholder call.
+	fenixLuaCallInputKey = "_FENIX_CALL_INPUT"
+	// fenixLuaRngTypeName is the metatable name of generators returned by fenix.rng.
+	fenixLuaRngTypeName = "fenix.rng"
+)
+
+// fenixLuaModuleFunctions are the functions of the 'fenix' module. They share their implementation with the Go
+// handlers, so a Lua function gets the same seeds, formatting and clock as the Go handlers.
+var fenixLuaModuleFunctions = map[string]lua.LGFunction{
+	"entropy":              fenixLuaEntropy,
+	"rng":                  fenixLuaRng,
+	"controlled_unique_id": fenixLuaControlledUniqueID,
+	"random_decimal":       fenixLuaRandomDecimal,
+	"round":                fenixLuaRound,
+	"format_decimal":       fenixLuaFormatDecimal,
+	"pad":                  fenixLuaPad,
+	"now":                  fenixLuaNow,
+	"time":                 fenixLuaTime,
+	"today_shift_day":      fenixLuaTodayShiftDay,
+	"testdata":             fenixLuaTestData,
+	"log":                  fenixLuaLog,
+	"store":                fenixLuaStore,
+}
+
+// preloadFenixLuaModule registers the 'fenix' module in package.preload and the holder of the call input.
+func preloadFenixLuaModule(L *lua.LState) {
+	L.SetField(L.Get(lua.RegistryIndex), fenixLuaCallInputKey, L.NewUserData())
+
+	rngMetatable := L.NewTypeMetatable(fenixLuaRngTypeName)
+	L.SetField(rngMetatable, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"float": fenixLuaRngFloat,
+		"int":   fenixLuaRngInt,
+	}))
+	storeMetatable := L.NewTypeMetatable(fenixLuaStoreTypeName)
+	L.SetField(storeMetatable, "__index", L.SetFuncs(L.NewTable(), fenixLuaStoreMethods))
+
+	L.PreloadModule(fenixLuaModuleName, func(L *lua.LState) int {
+		L.Push(L.SetFuncs(L.NewTable(), fenixLuaModuleFunctions))
+		return 1
+	})
+}
+
+// setFenixLuaCallInput makes the input of a placeholder call available to the 'fenix' module, nil when the call is done.
+func setFenixLuaCallInput(L *lua.LState, input *GoPlaceholderInput) {
+	if callInputHolder, ok := L.GetField(L.Get(lua.RegistryIndex), fenixLuaCallInputKey).(*lua.LUserData); ok == true {
+		callInputHolder.Value = input
+	}
+}
+
+// runningFenixLuaCallInput returns the input of the running placeholder call, nil outside a call.
+func runningFenixLuaCallInput(L *lua.LState) *GoPlaceholderInput {
+	if callInputHolder, ok := L.GetField(L.Get(lua.RegistryIndex), fenixLuaCallInputKey).(*lua.LUserData); ok == true {
+		if input, ok := callInputHolder.Value.(*GoPlaceholderInput); ok == true && input != nil {
+			return input
+		}
+	}
+
+	return nil
+}
+
+// fenixLuaCallInput returns the input of the running placeholder call. Outside a call, for example while a
+// script is loaded, a Lua error is raised.
+func fenixLuaCallInput(L *lua.LState, functionName string) *GoPlaceholderInput {
+	if input := runningFenixLuaCallInput(L); input != nil {
+		return input
+	}
+
+	L.RaiseError("fenix.%s can only be used during a placeholder call", functionName)
+	return nil
+}
+
+// fenixLuaEntropy implements fenix.entropy(): the entropy of the call, as given to Lua functions in the input table.
+func fenixLuaEntropy(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "entropy")
+	L.Push(lua.LNumber(input.Entropy))
+	return 1
+}
+
+// fenixLuaRng implements fenix.rng(arrayIndex): a generator seeded in the same way as the Go handlers seed one array index.
+func fenixLuaRng(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "rng")
+	arrayIndex := L.OptInt(1, 1)
+
+	generator := L.NewUserData()
+	generator.Value = rand.New(rand.NewSource(input.seedForArrayIndex(arrayIndex)))
+	L.SetMetatable(generator, L.GetTypeMetatable(fenixLuaRngTypeName))
+	L.Push(generator)
+	return 1
+}
+
+// checkFenixLuaRng returns the generator a method is called on.
+func checkFenixLuaRng(L *lua.LState) *rand.Rand {
+	if generator, ok := L.CheckUserData(1).Value.(*rand.Rand); ok == true {
+		return generator
+	}
+
+	L.ArgError(1, "fenix.rng generator expected")
+	return nil
+}
+
+// fenixLuaRngFloat implements generator:float(), a number in [0, 1).
+func fenixLuaRngFloat(L *lua.LState) int {
+	L.Push(lua.LNumber(checkFenixLuaRng(L).Float64()))
+	return 1
+}
+
+// fenixLuaRngInt implements generator:int(min, max), an integer in [min, max].
+func fenixLuaRngInt(L *lua.LState) int {
+	generator := checkFenixLuaRng(L)
+	minValue := L.CheckInt64(2)
+	maxValue := L.CheckInt64(3)
+	if maxValue < minValue {
+		L.ArgError(3, "max must not be less than min")
+	}
+
+	L.Push(lua.LNumber(minValue + generator.Int63n(maxValue-minValue+1)))
+	return 1
+}
+
+// fenixLuaControlledUniqueID implements fenix.controlled_unique_id(text [, arrayIndex]) with the entropy of the call.
+func fenixLuaControlledUniqueID(L *lua.LState) int {
+	input := *fenixLuaCallInput(L, "controlled_unique_id")
+	textToProcess := L.CheckString(1)
+
+	input.ArrayIndexes = []int{L.OptInt(2, 1)}
+	input.Arguments = []string{
+		textToProcess,
+		strconv.FormatBool(input.UseEntropyFromExecutionUUID),
+		strconv.FormatUint(input.ExtraEntropy, 10),
+	}
+
+	value, err := goFenixControlledUniqueID(input)
+
+	return pushFenixLuaResult(L, value, err)
+}
+
+// fenixLuaRandomDecimal implements
+// fenix.random_decimal(arrayIndex, integerPrecision, fractionPrecision [, integerWidth, fractionWidth, decimalPointCharacter]).
+func fenixLuaRandomDecimal(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "random_decimal")
+	arrayIndex := L.CheckInt(1)
+	functionArguments := []string{
+		strconv.Itoa(L.CheckInt(2)),
+		strconv.Itoa(L.CheckInt(3)),
+		strconv.Itoa(L.OptInt(4, 0)),
+		strconv.Itoa(L.OptInt(5, 0)),
+		L.OptString(6, "."),
+	}
+
+	integerArguments, decimalPointCharacter, err := parseRandomPositiveFunctionArguments(functionArguments)
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	L.Push(lua.LString(fenixRandomDecimalValueArrayValue(arrayIndex, integerArguments, decimalPointCharacter, input.seedForArrayIndex)))
+	return 1
+}
+
+// fenixLuaRound implements fenix.round(number, decimals).
+func fenixLuaRound(L *lua.LState) int {
+	L.Push(lua.LNumber(roundToDecimalPlaces(float64(L.CheckNumber(1)), L.CheckInt(2))))
+	return 1
+}
+
+// fenixLuaFormatDecimal implements fenix.format_decimal(number, decimals [, integerWidth, fractionWidth, decimalPointCharacter]).
+func fenixLuaFormatDecimal(L *lua.LState) int {
+	formattedValue := formatDecimalValue(float64(L.CheckNumber(1)), L.CheckInt(2))
+	formattedValue = padValueWithZeros(formattedValue, L.OptInt(3, 0), L.OptInt(4, 0))
+
+	L.Push(lua.LString(applyDecimalPointCharacter(formattedValue, L.OptString(5, "."))))
+	return 1
+}
+
+// fenixLuaPad implements fenix.pad(text, integerWidth, fractionWidth).
+func fenixLuaPad(L *lua.LState) int {
+	L.Push(lua.LString(padValueWithZeros(L.CheckString(1), L.CheckInt(2), L.OptInt(3, 0))))
+	return 1
+}
+
+// fenixLuaNow implements fenix.now([layout]): the execution clock in the execution time zone, formatted with a Go
+// layout. The default layout is RFC 3339.
+func fenixLuaNow(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "now")
+	L.Push(lua.LString(input.executionTime().Format(L.OptString(1, time.RFC3339))))
+	return 1
+}
+
+// fenixLuaTime implements fenix.time(): the execution clock as Unix time in seconds.
+func fenixLuaTime(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "time")
+	L.Push(lua.LNumber(input.executionTime().Unix()))
+	return 1
+}
+
+// fenixLuaTodayShiftDay implements fenix.today_shift_day(days), the date as 'YYYY-MM-DD' as Fenix.TodayShiftDay gives it.
+func fenixLuaTodayShiftDay(L *lua.LState) int {
+	input := *fenixLuaCallInput(L, "today_shift_day")
+	input.ArrayIndexes = nil
+	input.Arguments = []string{strconv.Itoa(L.CheckInt(1))}
+
+	value, err := goFenixTodayShiftDay(input)
+
+	return pushFenixLuaResult(L, value, err)
+}
+
+// fenixLuaTestData implements fenix.testdata(columnDataName): the TestData value of the rendering, or nil.
+func fenixLuaTestData(L *lua.LState) int {
+	input := fenixLuaCallInput(L, "testdata")
+	value, exists := input.TestDataValues[L.CheckString(1)]
+	if exists == false {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	L.Push(lua.LString(value))
+	return 1
+}
+
+// pushFenixLuaResult pushes the result of a shared Go handler, or raises its error in Lua.
+func pushFenixLuaResult(L *lua.LState, value string, err error) int {
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+
+	L.Push(lua.LString(value))
+	return 1
+}
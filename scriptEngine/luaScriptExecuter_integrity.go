@@ -0,0 +1,176 @@
+package scriptEngine
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// LuaScriptIntegrityOptions lists which domain scripts may be loaded. A script is loaded when its SHA-256 hash is
+// the one in the manifest for its name, or when its Signature verifies with one of the trusted public keys.
+// Other scripts are refused before any script runs. The Fenix scripts are part of the binary and always loaded.
+type LuaScriptIntegrityOptions struct {
+	// Allowed SHA-256 hash per script name, hex encoded, see ParseLuaScriptHashManifest.
+	AllowedSHA256 map[string]string
+	// Ed25519 public keys whose signatures over the script content are trusted.
+	TrustedPublicKeys []ed25519.PublicKey
+}
+
+// LuaScriptVerification tells why a script was allowed to load.
+type LuaScriptVerification string
+
+const (
+	// LuaScriptVerificationNone is a domain script loaded without integrity options.
+	LuaScriptVerificationNone LuaScriptVerification = "none"
+	// LuaScriptVerificationEmbedded is a Fenix script embedded in the binary.
+	LuaScriptVerificationEmbedded LuaScriptVerification = "embedded"
+	// LuaScriptVerificationManifest is a script with the SHA-256 hash given in the manifest.
+	LuaScriptVerificationManifest LuaScriptVerification = "manifest"
+	// LuaScriptVerificationSignature is a script with a valid signature from a trusted public key.
+	LuaScriptVerificationSignature LuaScriptVerification = "signature"
+)
+
+// luaScriptSignatureExtension is added to the script file name for the file that holds its signature.
+const luaScriptSignatureExtension = ".sig"
+
+// validate checks the hashes and keys, so a typo in the manifest is not reported as a refused script.
+func (integrityOptions *LuaScriptIntegrityOptions) validate() error {
+	for scriptName, allowedSHA256 := range integrityOptions.AllowedSHA256 {
+		if hashBytes, err := hex.DecodeString(allowedSHA256); err != nil || len(hashBytes) != sha256.Size {
+			return fmt.Errorf("manifest hash of Lua script '%s' must be %d hex encoded bytes, got '%s'", scriptName, sha256.Size, allowedSHA256)
+		}
+	}
+	for keyIndex, publicKey := range integrityOptions.TrustedPublicKeys {
+		if len(publicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("trusted public key %d must be %d bytes, got %d", keyIndex+1, ed25519.PublicKeySize, len(publicKey))
+		}
+	}
+
+	return nil
+}
+
+// verify checks one domain script. It returns how the script was verified and, for signed scripts, the hex
+// encoded public key of the signer.
+func (integrityOptions *LuaScriptIntegrityOptions) verify(luaScript LuaScriptsStruct, scriptSHA256 string) (
+	verification LuaScriptVerification, signedBy string, loadError *LuaScriptLoadError) {
+
+	allowedSHA256, inManifest := integrityOptions.AllowedSHA256[luaScript.LuaScriptName]
+	if inManifest == true && strings.EqualFold(allowedSHA256, scriptSHA256) == true {
+		return LuaScriptVerificationManifest, "", nil
+	}
+	if len(luaScript.Signature) > 0 {
+		for _, publicKey := range integrityOptions.TrustedPublicKeys {
+			if ed25519.Verify(publicKey, luaScript.LuaScript, luaScript.Signature) == true {
+				return LuaScriptVerificationSignature, hex.EncodeToString(publicKey), nil
+			}
+		}
+	}
+
+	var reasons []string
+	if inManifest == true {
+		reasons = append(reasons, fmt.Sprintf("SHA-256 %s does not match the manifest hash %s", scriptSHA256, strings.ToLower(allowedSHA256)))
+	} else {
+		reasons = append(reasons, fmt.Sprintf("SHA-256 %s is not in the manifest", scriptSHA256))
+	}
+	if len(luaScript.Signature) > 0 {
+		reasons = append(reasons, "the signature is not valid for any trusted public key")
+	} else {
+		reasons = append(reasons, "the script is not signed")
+	}
+	message := strings.Join(reasons, " and ")
+
+	return "", "", &LuaScriptLoadError{ScriptName: luaScript.LuaScriptName, Phase: LuaScriptLoadPhaseIntegrity,
+		Message: message, Err: fmt.Errorf("%s", message)}
+}
+
+// verifyLuaScripts hashes all scripts and verifies the domain scripts, which follow the first numberOfFenixScripts.
+// The returned reports have the name, hash and verification of every script, in load order.
+func verifyLuaScripts(luaScriptFiles []LuaScriptsStruct, numberOfFenixScripts int, luaEngineOptions LuaEngineOptions) (
+	scriptLoadReports []LuaScriptLoadReport, integrityErrors LuaScriptLoadErrors) {
+
+	for scriptIndex, luaScriptFile := range luaScriptFiles {
+		scriptLoadReport := LuaScriptLoadReport{
+			ScriptName:   luaScriptFile.LuaScriptName,
+			SHA256:       luaScriptSHA256(luaScriptFile.LuaScript),
+			Verification: LuaScriptVerificationNone,
+		}
+
+		switch {
+		case scriptIndex < numberOfFenixScripts:
+			scriptLoadReport.Verification = LuaScriptVerificationEmbedded
+		case luaEngineOptions.Integrity != nil:
+			verification, signedBy, integrityError := luaEngineOptions.Integrity.verify(luaScriptFile, scriptLoadReport.SHA256)
+			if integrityError != nil {
+				integrityErrors = append(integrityErrors, integrityError)
+			}
+			scriptLoadReport.Verification = verification
+			scriptLoadReport.SignedBy = signedBy
+		}
+		scriptLoadReports = append(scriptLoadReports, scriptLoadReport)
+	}
+
+	return scriptLoadReports, integrityErrors
+}
+
+// luaScriptSHA256 returns the hex encoded SHA-256 hash of a script.
+func luaScriptSHA256(luaScript []byte) string {
+	hash := sha256.Sum256(luaScript)
+	return hex.EncodeToString(hash[:])
+}
+
+// ParseLuaScriptHashManifest reads a manifest in the format written by 'sha256sum', one '<hash>  <file>' per line.
+// The script name is the file path without '.lua', like LoadLuaScriptsFromFS names scripts, so a manifest made
+// with 'sha256sum $(find . -name "*.lua")' in the script directory matches. Empty lines and '#' comments are skipped.
+func ParseLuaScriptHashManifest(reader io.Reader) (map[string]string, error) {
+	allowedSHA256 := map[string]string{}
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") == true {
+			continue
+		}
+
+		scriptSHA256, filePath, found := strings.Cut(line, " ")
+		filePath = strings.TrimPrefix(strings.TrimSpace(filePath), "*")
+		if found == false || filePath == "" {
+			return nil, fmt.Errorf("manifest line %d must be '<sha256>  <file>', got '%s'", lineNumber, line)
+		}
+		if hashBytes, err := hex.DecodeString(scriptSHA256); err != nil || len(hashBytes) != sha256.Size {
+			return nil, fmt.Errorf("manifest line %d has an invalid SHA-256 hash '%s'", lineNumber, scriptSHA256)
+		}
+
+		scriptName := strings.TrimSuffix(path.Clean(strings.TrimPrefix(filePath, "./")), ".lua")
+		if previousSHA256, exists := allowedSHA256[scriptName]; exists == true && previousSHA256 != strings.ToLower(scriptSHA256) {
+			return nil, fmt.Errorf("manifest line %d gives Lua script '%s' a second hash", lineNumber, scriptName)
+		}
+		allowedSHA256[scriptName] = strings.ToLower(scriptSHA256)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return allowedSHA256, nil
+}
+
+// decodeLuaScriptSignature reads a signature file, which holds the 64 signature bytes or them base64 encoded.
+func decodeLuaScriptSignature(signatureFile []byte) ([]byte, error) {
+	if len(signatureFile) == ed25519.SignatureSize {
+		return signatureFile, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signatureFile)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("signature must be %d bytes, raw or base64 encoded", ed25519.SignatureSize)
+	}
+
+	return signature, nil
+}
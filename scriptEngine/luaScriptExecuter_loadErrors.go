@@ -0,0 +1,201 @@
+package scriptEngine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// LuaScriptLoadPhase tells whether a script failed when it was compiled, when it was run, when the metadata
+// of its functions was read, when the modules it requires were checked or when its integrity was verified.
+type LuaScriptLoadPhase string
+
+const (
+	LuaScriptLoadPhaseCompile    LuaScriptLoadPhase = "compile"
+	LuaScriptLoadPhaseRun        LuaScriptLoadPhase = "run"
+	LuaScriptLoadPhaseMetadata   LuaScriptLoadPhase = "metadata"
+	LuaScriptLoadPhaseDependency LuaScriptLoadPhase = "dependency"
+	LuaScriptLoadPhaseIntegrity  LuaScriptLoadPhase = "integrity"
+)
+
+// LuaScriptLoadError is the failure of one script while the engine was loading it.
+type LuaScriptLoadError struct {
+	ScriptName string
+	Phase      LuaScriptLoadPhase
+	// Line in the script, 0 when unknown, for example for syntax errors at the end of the script.
+	Line    int
+	Message string
+	// Error returned by gopher-lua.
+	Err error
+}
+
+func (loadError *LuaScriptLoadError) Error() string {
+	failure := "failed to " + string(loadError.Phase)
+	switch loadError.Phase {
+	case LuaScriptLoadPhaseMetadata:
+		failure = "has invalid metadata"
+	case LuaScriptLoadPhaseDependency:
+		failure = "has a dependency error"
+	case LuaScriptLoadPhaseIntegrity:
+		failure = "failed integrity verification"
+	}
+
+	if loadError.Line > 0 {
+		return fmt.Sprintf("Lua script '%s' %s at line %d: %s", loadError.ScriptName, failure, loadError.Line, loadError.Message)
+	}
+
+	return fmt.Sprintf("Lua script '%s' %s: %s", loadError.ScriptName, failure, loadError.Message)
+}
+
+func (loadError *LuaScriptLoadError) Unwrap() error {
+	return loadError.Err
+}
+
+// LuaScriptLoadErrors is returned when one or more scripts failed to load.
+type LuaScriptLoadErrors []*LuaScriptLoadError
+
+func (loadErrors LuaScriptLoadErrors) Error() string {
+	messages := make([]string, 0, len(loadErrors))
+	for _, loadError := range loadErrors {
+		messages = append(messages, loadError.Error())
+	}
+
+	return fmt.Sprintf("%d Lua script(s) failed to load:\n%s", len(loadErrors), strings.Join(messages, "\n"))
+}
+
+func (loadErrors LuaScriptLoadErrors) Unwrap() []error {
+	wrappedErrors := make([]error, 0, len(loadErrors))
+	for _, loadError := range loadErrors {
+		wrappedErrors = append(wrappedErrors, loadError)
+	}
+
+	return wrappedErrors
+}
+
+// LuaScriptLoadReport tells which script was loaded, with its hash and verification, and which global functions
+// it defined when it was loaded.
+type LuaScriptLoadReport struct {
+	ScriptName string `json:"scriptName"`
+	// Name the script is required with, empty when the script is not a module. Modules define no functions when loaded.
+	ModuleName string `json:"moduleName,omitempty"`
+	// Hex encoded SHA-256 hash of the script content.
+	SHA256       string                `json:"sha256"`
+	Verification LuaScriptVerification `json:"verification"`
+	// Hex encoded Ed25519 public key that signed the script, for verification 'signature'.
+	SignedBy string `json:"signedBy,omitempty"`
+	// Global functions defined or redefined by the script, sorted by name.
+	DefinedFunctions []string `json:"definedFunctions,omitempty"`
+}
+
+// GetLuaScriptLoadReport returns one report per loaded script, in load order. It is empty when the engine is not initiated.
+func GetLuaScriptLoadReport() []LuaScriptLoadReport {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
+
+	if luaEngine == nil {
+		return nil
+	}
+
+	return append([]LuaScriptLoadReport{}, luaEngine.scriptLoadReports...)
+}
+
+// newLuaScriptCompileError converts a gopher-lua compile error. Syntax errors carry their line in a parse.Error.
+func newLuaScriptCompileError(scriptName string, err error) *LuaScriptLoadError {
+	loadError := &LuaScriptLoadError{ScriptName: scriptName, Phase: LuaScriptLoadPhaseCompile, Message: err.Error(), Err: err}
+
+	var apiError *lua.ApiError
+	if errors.As(err, &apiError) == true {
+		var parseError *parse.Error
+		if errors.As(apiError.Cause, &parseError) == true {
+			loadError.Message = parseError.Message
+			if parseError.Token != "" {
+				loadError.Message = fmt.Sprintf("%s near '%s'", parseError.Message, parseError.Token)
+			}
+			if parseError.Pos.Line > 0 {
+				loadError.Line = parseError.Pos.Line
+			}
+		}
+	}
+
+	return loadError
+}
+
+// newLuaScriptRunError converts a gopher-lua run error. The message starts with '<scriptName>:<line>:' when
+// the error was raised in the script itself.
+func newLuaScriptRunError(scriptName string, err error) *LuaScriptLoadError {
+	loadError := &LuaScriptLoadError{ScriptName: scriptName, Phase: LuaScriptLoadPhaseRun, Message: err.Error(), Err: err}
+
+	var apiError *lua.ApiError
+	if errors.As(err, &apiError) == true && apiError.Object != nil {
+		loadError.Message = apiError.Object.String()
+		if lineAndMessage, found := strings.CutPrefix(loadError.Message, scriptName+":"); found == true {
+			lineText, message, found := strings.Cut(lineAndMessage, ":")
+			if line, convertErr := strconv.Atoi(lineText); found == true && convertErr == nil {
+				loadError.Line = line
+				loadError.Message = strings.TrimSpace(message)
+			}
+		}
+	}
+
+	return loadError
+}
+
+// newLuaScriptDependencyError is a module that is missing, used twice or part of a require cycle.
+func newLuaScriptDependencyError(scriptName string, line int, message string) *LuaScriptLoadError {
+	return &LuaScriptLoadError{ScriptName: scriptName, Phase: LuaScriptLoadPhaseDependency, Line: line, Message: message,
+		Err: errors.New(message)}
+}
+
+// withLuaModuleDependencyErrors puts the dependency errors first. Run errors of scripts with a dependency error are
+// dropped, they only repeat that require failed.
+func withLuaModuleDependencyErrors(loadErrors LuaScriptLoadErrors, dependencyErrors LuaScriptLoadErrors) LuaScriptLoadErrors {
+	if len(dependencyErrors) == 0 {
+		return loadErrors
+	}
+
+	scriptsWithDependencyErrors := map[string]bool{}
+	for _, dependencyError := range dependencyErrors {
+		scriptsWithDependencyErrors[dependencyError.ScriptName] = true
+	}
+	allLoadErrors := append(LuaScriptLoadErrors{}, dependencyErrors...)
+	for _, loadError := range loadErrors {
+		if loadError.Phase == LuaScriptLoadPhaseRun && scriptsWithDependencyErrors[loadError.ScriptName] == true {
+			continue
+		}
+		allLoadErrors = append(allLoadErrors, loadError)
+	}
+
+	return allLoadErrors
+}
+
+// luaGlobalFunctions returns the global functions of a Lua state.
+func luaGlobalFunctions(L *lua.LState) map[string]*lua.LFunction {
+	globalFunctions := map[string]*lua.LFunction{}
+	if globals, ok := L.Get(lua.GlobalsIndex).(*lua.LTable); ok == true {
+		globals.ForEach(func(key lua.LValue, value lua.LValue) {
+			if function, ok := value.(*lua.LFunction); ok == true {
+				globalFunctions[key.String()] = function
+			}
+		})
+	}
+
+	return globalFunctions
+}
+
+// definedLuaFunctions returns the names of functions that are new or replaced compared to an earlier snapshot.
+func definedLuaFunctions(before map[string]*lua.LFunction, after map[string]*lua.LFunction) []string {
+	var functionNames []string
+	for functionName, function := range after {
+		if before[functionName] != function {
+			functionNames = append(functionNames, functionName)
+		}
+	}
+	sort.Strings(functionNames)
+
+	return functionNames
+}
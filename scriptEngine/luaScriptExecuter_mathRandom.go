@@ -0,0 +1,44 @@
+package scriptEngine
+
+import (
+	"math/rand"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// replaceLuaMathRandom gives the Lua state its own random generator for 'math.random' and 'math.randomseed'.
+// gopher-lua seeds Go's global generator, and 'rand.Seed' does nothing since Go 1.24, so the same seed gave
+// different values on every run. With one generator per state, a seed gives the same values again.
+func replaceLuaMathRandom(L *lua.LState) {
+	mathTable, ok := L.GetGlobal("math").(*lua.LTable)
+	if ok == false {
+		return
+	}
+
+	// Without 'math.randomseed' the values differ per state, like before
+	randomGenerator := rand.New(rand.NewSource(rand.Int63()))
+
+	L.SetField(mathTable, "random", L.NewFunction(func(L *lua.LState) int {
+		switch L.GetTop() {
+		case 0:
+			L.Push(lua.LNumber(randomGenerator.Float64()))
+		case 1:
+			upperLimit := L.CheckInt(1)
+			if upperLimit < 1 {
+				L.ArgError(1, "interval is empty")
+			}
+			L.Push(lua.LNumber(randomGenerator.Intn(upperLimit) + 1))
+		default:
+			lowerLimit, upperLimit := L.CheckInt(1), L.CheckInt(2)
+			if lowerLimit > upperLimit {
+				L.ArgError(2, "interval is empty")
+			}
+			L.Push(lua.LNumber(randomGenerator.Intn(upperLimit-lowerLimit+1) + lowerLimit))
+		}
+		return 1
+	}))
+	L.SetField(mathTable, "randomseed", L.NewFunction(func(L *lua.LState) int {
+		randomGenerator.Seed(L.CheckInt64(1))
+		return 0
+	}))
+}
@@ -0,0 +1,187 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"sort"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// luaPlaceholderMetadataSuffix is appended to a function name to get the global table with its metadata,
+// for example 'HappyLuaTime_meta'.
+const luaPlaceholderMetadataSuffix = "_meta"
+
+// readLuaPlaceholderMetadata reads the metadata table of every function the scripts defined. Invalid metadata is
+// reported as a load error of the script that defined the function.
+func readLuaPlaceholderMetadata(L *lua.LState, scriptLoadReports []LuaScriptLoadReport) (map[string]PlaceholderFunctionMetadata, LuaScriptLoadErrors) {
+	// The last script that defined a function owns it
+	scriptNamePerFunction := map[string]string{}
+	for _, scriptLoadReport := range scriptLoadReports {
+		for _, functionName := range scriptLoadReport.DefinedFunctions {
+			scriptNamePerFunction[functionName] = scriptLoadReport.ScriptName
+		}
+	}
+
+	functionNames := make([]string, 0, len(scriptNamePerFunction))
+	for functionName := range scriptNamePerFunction {
+		functionNames = append(functionNames, functionName)
+	}
+	sort.Strings(functionNames)
+
+	functionMetadata := map[string]PlaceholderFunctionMetadata{}
+	var loadErrors LuaScriptLoadErrors
+	for _, functionName := range functionNames {
+		metadataValue := L.GetGlobal(functionName + luaPlaceholderMetadataSuffix)
+		if metadataValue == lua.LNil {
+			continue
+		}
+
+		metadata, err := luaTableToPlaceholderMetadata(functionName, metadataValue)
+		if err != nil {
+			loadErrors = append(loadErrors, &LuaScriptLoadError{
+				ScriptName: scriptNamePerFunction[functionName],
+				Phase:      LuaScriptLoadPhaseMetadata,
+				Message:    fmt.Sprintf("%s%s: %s", functionName, luaPlaceholderMetadataSuffix, err.Error()),
+				Err:        err,
+			})
+			continue
+		}
+		functionMetadata[functionName] = metadata
+	}
+
+	return functionMetadata, loadErrors
+}
+
+// luaTableToPlaceholderMetadata converts a metadata table:
+//
+//	{ description = "...", args = { { name = "days", type = "integer", description = "...", optional = false } },
+//	  array_indexes = "none", examples = { "{{Fenix.TodayShiftDay(1)}}" } }
+func luaTableToPlaceholderMetadata(functionName string, metadataValue lua.LValue) (metadata PlaceholderFunctionMetadata, err error) {
+	metadataTable, ok := metadataValue.(*lua.LTable)
+	if ok == false {
+		return metadata, fmt.Errorf("metadata must be a table, got %s", metadataValue.Type())
+	}
+
+	metadata = PlaceholderFunctionMetadata{FunctionName: functionName, Runtime: PlaceholderRuntimeLua, Declared: true}
+	if metadata.Description, err = luaMetadataString(metadataTable, "description"); err != nil {
+		return metadata, err
+	}
+
+	arrayIndexes, err := luaMetadataString(metadataTable, "array_indexes")
+	if err != nil {
+		return metadata, err
+	}
+	metadata.ArrayIndexes = PlaceholderArrayIndexes(arrayIndexes)
+
+	if examplesValue := metadataTable.RawGetString("examples"); examplesValue != lua.LNil {
+		examplesTable, ok := examplesValue.(*lua.LTable)
+		if ok == false {
+			return metadata, fmt.Errorf("'examples' must be a list of strings")
+		}
+		for exampleIndex := 1; exampleIndex <= examplesTable.Len(); exampleIndex++ {
+			example, ok := examplesTable.RawGetInt(exampleIndex).(lua.LString)
+			if ok == false {
+				return metadata, fmt.Errorf("'examples' must be a list of strings")
+			}
+			metadata.Examples = append(metadata.Examples, string(example))
+		}
+	}
+
+	if argumentsValue := metadataTable.RawGetString("args"); argumentsValue != lua.LNil {
+		argumentsTable, ok := argumentsValue.(*lua.LTable)
+		if ok == false {
+			return metadata, fmt.Errorf("'args' must be a list of tables")
+		}
+		metadata.Arguments = []PlaceholderArgumentMetadata{}
+		for argumentIndex := 1; argumentIndex <= argumentsTable.Len(); argumentIndex++ {
+			argument, err := luaTableToArgumentMetadata(argumentsTable.RawGetInt(argumentIndex))
+			if err != nil {
+				return metadata, fmt.Errorf("'args' entry %d: %w", argumentIndex, err)
+			}
+			metadata.Arguments = append(metadata.Arguments, argument)
+		}
+	}
+
+	return metadata, metadata.validate()
+}
+
+// luaTableToArgumentMetadata converts one entry of 'args'.
+func luaTableToArgumentMetadata(argumentValue lua.LValue) (argument PlaceholderArgumentMetadata, err error) {
+	argumentTable, ok := argumentValue.(*lua.LTable)
+	if ok == false {
+		return argument, fmt.Errorf("must be a table, got %s", argumentValue.Type())
+	}
+
+	if argument.Name, err = luaMetadataString(argumentTable, "name"); err != nil {
+		return argument, err
+	}
+	argumentType, err := luaMetadataString(argumentTable, "type")
+	if err != nil {
+		return argument, err
+	}
+	argument.Type = PlaceholderArgumentType(argumentType)
+	if argument.Description, err = luaMetadataString(argumentTable, "description"); err != nil {
+		return argument, err
+	}
+
+	switch optional := argumentTable.RawGetString("optional").(type) {
+	case lua.LBool:
+		argument.Optional = bool(optional)
+	default:
+		if optional != lua.LNil {
+			return argument, fmt.Errorf("'optional' must be a boolean")
+		}
+	}
+
+	return argument, nil
+}
+
+// luaMetadataString returns a string field, or "" when the field is not set.
+func luaMetadataString(table *lua.LTable, fieldName string) (string, error) {
+	switch value := table.RawGetString(fieldName).(type) {
+	case lua.LString:
+		return string(value), nil
+	default:
+		if value != lua.LNil {
+			return "", fmt.Errorf("'%s' must be a string, got %s", fieldName, value.Type())
+		}
+		return "", nil
+	}
+}
+
+// lookupLuaPlaceholderMetadata returns the declared metadata of a Lua function.
+func lookupLuaPlaceholderMetadata(functionName string) (PlaceholderFunctionMetadata, bool) {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
+
+	if luaEngine == nil {
+		return PlaceholderFunctionMetadata{}, false
+	}
+
+	metadata, exists := luaEngine.functionMetadata[functionName]
+	return metadata, exists
+}
+
+// luaPlaceholderFunctionNames returns the functions defined by the loaded scripts, without the Lua standard library.
+func luaPlaceholderFunctionNames() []string {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
+
+	if luaEngine == nil {
+		return nil
+	}
+
+	definedFunctions := map[string]bool{}
+	for _, scriptLoadReport := range luaEngine.scriptLoadReports {
+		for _, functionName := range scriptLoadReport.DefinedFunctions {
+			definedFunctions[functionName] = true
+		}
+	}
+
+	functionNames := make([]string, 0, len(definedFunctions))
+	for functionName := range definedFunctions {
+		functionNames = append(functionNames, functionName)
+	}
+
+	return functionNames
+}
@@ -0,0 +1,266 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+)
+
+// luaModuleName returns the name a module script is required with.
+func (luaScript LuaScriptsStruct) luaModuleName() string {
+	if luaScript.ModuleName != "" {
+		return luaScript.ModuleName
+	}
+
+	return luaScript.LuaScriptName
+}
+
+// registerLuaModule compiles a module script and registers it in package.preload. The module runs the first time
+// it is required, and what it returns is what require returns.
+func registerLuaModule(L *lua.LState, luaScript LuaScriptsStruct) *LuaScriptLoadError {
+	moduleFunction, err := loadCompiledLuaScript(L, luaScript)
+	if err != nil {
+		return newLuaScriptCompileError(luaScript.LuaScriptName, err)
+	}
+
+	L.PreloadModule(luaScript.luaModuleName(), func(L *lua.LState) int {
+		L.Push(moduleFunction)
+		L.Call(0, 1)
+		return 1
+	})
+
+	return nil
+}
+
+// luaRequire is a require call with a literal module name.
+type luaRequire struct {
+	moduleName string
+	line       int
+	// The call is made when the script runs, not later from one of its functions.
+	whenRun bool
+}
+
+// findLuaRequires returns the require calls with a literal module name. Scripts that don't compile have none,
+// their syntax error is reported when they are compiled.
+func findLuaRequires(luaScript LuaScriptsStruct) []luaRequire {
+	compiledScript, err := compileLuaScript(luaScript)
+	if err != nil {
+		return nil
+	}
+
+	return compiledScript.luaRequires
+}
+
+// visitLuaStatements collects the require calls of statements, whenRun is false inside function bodies.
+func visitLuaStatements(statements []ast.Stmt, whenRun bool, luaRequires *[]luaRequire) {
+	for _, statement := range statements {
+		switch statement := statement.(type) {
+		case *ast.AssignStmt:
+			visitLuaExpressions(statement.Lhs, whenRun, luaRequires)
+			visitLuaExpressions(statement.Rhs, whenRun, luaRequires)
+		case *ast.LocalAssignStmt:
+			visitLuaExpressions(statement.Exprs, whenRun, luaRequires)
+		case *ast.FuncCallStmt:
+			visitLuaExpression(statement.Expr, whenRun, luaRequires)
+		case *ast.DoBlockStmt:
+			visitLuaStatements(statement.Stmts, whenRun, luaRequires)
+		case *ast.WhileStmt:
+			visitLuaExpression(statement.Condition, whenRun, luaRequires)
+			visitLuaStatements(statement.Stmts, whenRun, luaRequires)
+		case *ast.RepeatStmt:
+			visitLuaExpression(statement.Condition, whenRun, luaRequires)
+			visitLuaStatements(statement.Stmts, whenRun, luaRequires)
+		case *ast.IfStmt:
+			visitLuaExpression(statement.Condition, whenRun, luaRequires)
+			visitLuaStatements(statement.Then, whenRun, luaRequires)
+			visitLuaStatements(statement.Else, whenRun, luaRequires)
+		case *ast.NumberForStmt:
+			visitLuaExpressions([]ast.Expr{statement.Init, statement.Limit, statement.Step}, whenRun, luaRequires)
+			visitLuaStatements(statement.Stmts, whenRun, luaRequires)
+		case *ast.GenericForStmt:
+			visitLuaExpressions(statement.Exprs, whenRun, luaRequires)
+			visitLuaStatements(statement.Stmts, whenRun, luaRequires)
+		case *ast.FuncDefStmt:
+			visitLuaExpression(statement.Func, whenRun, luaRequires)
+		case *ast.ReturnStmt:
+			visitLuaExpressions(statement.Exprs, whenRun, luaRequires)
+		}
+	}
+}
+
+// visitLuaExpressions collects the require calls of expressions.
+func visitLuaExpressions(expressions []ast.Expr, whenRun bool, luaRequires *[]luaRequire) {
+	for _, expression := range expressions {
+		visitLuaExpression(expression, whenRun, luaRequires)
+	}
+}
+
+// visitLuaExpression collects the require calls of one expression.
+func visitLuaExpression(expression ast.Expr, whenRun bool, luaRequires *[]luaRequire) {
+	switch expression := expression.(type) {
+	case *ast.FuncCallExpr:
+		if function, ok := expression.Func.(*ast.IdentExpr); ok == true && function.Value == "require" && len(expression.Args) == 1 {
+			if moduleName, ok := expression.Args[0].(*ast.StringExpr); ok == true {
+				*luaRequires = append(*luaRequires, luaRequire{moduleName: moduleName.Value, line: expression.Line(), whenRun: whenRun})
+			}
+		}
+		visitLuaExpressions([]ast.Expr{expression.Func, expression.Receiver}, whenRun, luaRequires)
+		visitLuaExpressions(expression.Args, whenRun, luaRequires)
+	case *ast.AttrGetExpr:
+		visitLuaExpressions([]ast.Expr{expression.Object, expression.Key}, whenRun, luaRequires)
+	case *ast.TableExpr:
+		for _, field := range expression.Fields {
+			visitLuaExpressions([]ast.Expr{field.Key, field.Value}, whenRun, luaRequires)
+		}
+	case *ast.LogicalOpExpr:
+		visitLuaExpressions([]ast.Expr{expression.Lhs, expression.Rhs}, whenRun, luaRequires)
+	case *ast.RelationalOpExpr:
+		visitLuaExpressions([]ast.Expr{expression.Lhs, expression.Rhs}, whenRun, luaRequires)
+	case *ast.StringConcatOpExpr:
+		visitLuaExpressions([]ast.Expr{expression.Lhs, expression.Rhs}, whenRun, luaRequires)
+	case *ast.ArithmeticOpExpr:
+		visitLuaExpressions([]ast.Expr{expression.Lhs, expression.Rhs}, whenRun, luaRequires)
+	case *ast.UnaryMinusOpExpr:
+		visitLuaExpression(expression.Expr, whenRun, luaRequires)
+	case *ast.UnaryNotOpExpr:
+		visitLuaExpression(expression.Expr, whenRun, luaRequires)
+	case *ast.UnaryLenOpExpr:
+		visitLuaExpression(expression.Expr, whenRun, luaRequires)
+	case *ast.FunctionExpr:
+		visitLuaStatements(expression.Stmts, false, luaRequires)
+	}
+}
+
+// checkLuaModuleDependencies reports module names used twice, require calls of modules that don't exist and
+// modules that require each other when they run. A loaded Lua state is used to see which modules exist.
+func checkLuaModuleDependencies(L *lua.LState, luaScriptFiles []LuaScriptsStruct, luaEngineOptions LuaEngineOptions) (
+	dependencyErrors LuaScriptLoadErrors) {
+
+	// Script name per module name, built-in modules have no script
+	moduleScriptNames := map[string]string{}
+	for _, luaScriptFile := range luaScriptFiles {
+		if luaScriptFile.IsModule == false {
+			continue
+		}
+		moduleName := luaScriptFile.luaModuleName()
+		if otherScriptName, exists := moduleScriptNames[moduleName]; exists == true {
+			dependencyErrors = append(dependencyErrors, newLuaScriptDependencyError(luaScriptFile.LuaScriptName, 0,
+				fmt.Sprintf("module name '%s' is also used by script '%s'", moduleName, otherScriptName)))
+			continue
+		}
+		if isLuaBuiltInModule(moduleName) == true {
+			dependencyErrors = append(dependencyErrors, newLuaScriptDependencyError(luaScriptFile.LuaScriptName, 0,
+				fmt.Sprintf("module name '%s' is used by a built-in module", moduleName)))
+			continue
+		}
+		moduleScriptNames[moduleName] = luaScriptFile.LuaScriptName
+	}
+
+	// Modules each module requires when it runs
+	modulesRequiredWhenRun := map[string][]string{}
+	for _, luaScriptFile := range luaScriptFiles {
+		for _, luaRequire := range findLuaRequires(luaScriptFile) {
+			_, isModuleScript := moduleScriptNames[luaRequire.moduleName]
+			if isModuleScript == false && luaModuleExists(L, luaRequire.moduleName, luaEngineOptions) == false {
+				dependencyErrors = append(dependencyErrors, newLuaScriptDependencyError(luaScriptFile.LuaScriptName, luaRequire.line,
+					fmt.Sprintf("requires module '%s', which is not registered", luaRequire.moduleName)))
+				continue
+			}
+			if luaScriptFile.IsModule == true && luaRequire.whenRun == true {
+				moduleName := luaScriptFile.luaModuleName()
+				modulesRequiredWhenRun[moduleName] = append(modulesRequiredWhenRun[moduleName], luaRequire.moduleName)
+			}
+		}
+	}
+
+	moduleNames := make([]string, 0, len(moduleScriptNames))
+	for moduleName := range moduleScriptNames {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+	reportedCycles := map[string]bool{}
+	for _, moduleName := range moduleNames {
+		cycle := findLuaModuleCycle(moduleName, []string{moduleName}, modulesRequiredWhenRun)
+		if cycle == nil {
+			continue
+		}
+		cycleMembers := append([]string{}, cycle[:len(cycle)-1]...)
+		sort.Strings(cycleMembers)
+		if cycleKey := strings.Join(cycleMembers, "\x00"); reportedCycles[cycleKey] == false {
+			reportedCycles[cycleKey] = true
+			dependencyErrors = append(dependencyErrors, newLuaScriptDependencyError(moduleScriptNames[moduleName], 0,
+				fmt.Sprintf("module '%s' requires itself when it runs: %s", moduleName, strings.Join(cycle, " -> "))))
+		}
+	}
+
+	return dependencyErrors
+}
+
+// findLuaModuleCycle returns the require path from a module back to itself, or nil when there is none.
+func findLuaModuleCycle(startModuleName string, path []string, modulesRequiredWhenRun map[string][]string) []string {
+	for _, requiredModuleName := range modulesRequiredWhenRun[path[len(path)-1]] {
+		if requiredModuleName == startModuleName {
+			return append(append([]string{}, path...), requiredModuleName)
+		}
+		visited := false
+		for _, moduleName := range path {
+			visited = visited || moduleName == requiredModuleName
+		}
+		if visited == true {
+			continue
+		}
+		if cycle := findLuaModuleCycle(startModuleName, append(path, requiredModuleName), modulesRequiredWhenRun); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// luaBuiltInModuleNames are modules every Lua state has besides the standard libraries.
+var luaBuiltInModuleNames = []string{"date", fenixLuaModuleName}
+
+// isLuaBuiltInModule returns true for the name of a built-in module.
+func isLuaBuiltInModule(moduleName string) bool {
+	for _, builtInModuleName := range luaBuiltInModuleNames {
+		if builtInModuleName == moduleName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// luaModuleExists returns true when require can find the module: it is preloaded, already loaded, or, outside
+// the sandbox, a file on package.path.
+func luaModuleExists(L *lua.LState, moduleName string, luaEngineOptions LuaEngineOptions) bool {
+	packageTable, ok := L.GetGlobal("package").(*lua.LTable)
+	if ok == false {
+		return false
+	}
+	for _, fieldName := range []string{"preload", "loaded"} {
+		if moduleTable, ok := packageTable.RawGetString(fieldName).(*lua.LTable); ok == true && moduleTable.RawGetString(moduleName) != lua.LNil {
+			return true
+		}
+	}
+	if luaEngineOptions.Sandbox == true {
+		return false
+	}
+
+	packagePath := lua.LVAsString(packageTable.RawGetString("path"))
+	for _, pathTemplate := range strings.Split(packagePath, ";") {
+		if pathTemplate == "" {
+			continue
+		}
+		filePath := strings.ReplaceAll(pathTemplate, "?", strings.ReplaceAll(moduleName, ".", string(os.PathSeparator)))
+		if fileInfo, err := os.Stat(filePath); err == nil && fileInfo.IsDir() == false {
+			return true
+		}
+	}
+
+	return false
+}
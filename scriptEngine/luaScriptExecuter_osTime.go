@@ -0,0 +1,128 @@
+package scriptEngine
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// luaDateFormatsToGo maps the 'os.date' conversions to Go layouts, like gopher-lua does.
+var luaDateFormatsToGo = map[byte]string{
+	'a': "Mon", 'A': "Monday", 'b': "Jan", 'B': "January", 'c': "02 Jan 06 15:04 MST", 'd': "02",
+	'F': "2006-01-02", 'H': "15", 'I': "03", 'm': "01", 'M': "04", 'p': "PM", 'P': "pm", 'S': "05",
+	'x': "15/04/05", 'X': "15:04:05", 'y': "06", 'Y': "2006", 'z': "-0700", 'Z': "MST"}
+
+// replaceLuaOsTime makes 'os.date' and 'os.time' read the clock and time zone of the running placeholder call,
+// like the Go handlers do, so Lua date and time tokens follow a fixed or shifted execution clock.
+// Outside a call, for example while a script is loaded, they read the engine clock in local time.
+func replaceLuaOsTime(L *lua.LState) {
+	osTable, ok := L.GetGlobal("os").(*lua.LTable)
+	if ok == false {
+		return
+	}
+
+	L.SetField(osTable, "date", L.NewFunction(luaOsDate))
+	L.SetField(osTable, "time", L.NewFunction(luaOsTime))
+}
+
+// luaExecutionTime returns the clock instant of the running placeholder call.
+func luaExecutionTime(L *lua.LState) time.Time {
+	if input := runningFenixLuaCallInput(L); input != nil {
+		return input.executionTime()
+	}
+
+	return GoPlaceholderInput{}.executionTime()
+}
+
+// luaOsDate implements os.date([format [, time]]). A format starting with '!' gives UTC, '*t' gives a table.
+func luaOsDate(L *lua.LState) int {
+	executionTime := luaExecutionTime(L)
+	dateTime := executionTime
+	format := L.OptString(1, "%c")
+	if L.GetTop() >= 2 {
+		dateTime = time.Unix(L.CheckInt64(2), 0).In(executionTime.Location())
+	}
+	if strings.HasPrefix(format, "!") == true {
+		format = strings.TrimPrefix(format, "!")
+		dateTime = dateTime.UTC()
+	}
+
+	if strings.HasPrefix(format, "*t") == true {
+		dateTable := L.NewTable()
+		dateTable.RawSetString("year", lua.LNumber(dateTime.Year()))
+		dateTable.RawSetString("month", lua.LNumber(dateTime.Month()))
+		dateTable.RawSetString("day", lua.LNumber(dateTime.Day()))
+		dateTable.RawSetString("hour", lua.LNumber(dateTime.Hour()))
+		dateTable.RawSetString("min", lua.LNumber(dateTime.Minute()))
+		dateTable.RawSetString("sec", lua.LNumber(dateTime.Second()))
+		dateTable.RawSetString("wday", lua.LNumber(dateTime.Weekday()+1))
+		dateTable.RawSetString("yday", lua.LNumber(dateTime.YearDay()))
+		dateTable.RawSetString("isdst", lua.LBool(dateTime.IsDST()))
+		L.Push(dateTable)
+		return 1
+	}
+
+	L.Push(lua.LString(formatLuaDate(dateTime, format)))
+	return 1
+}
+
+// luaOsTime implements os.time([table]). A table is read as a date in the time zone of the running call.
+func luaOsTime(L *lua.LState) int {
+	executionTime := luaExecutionTime(L)
+	if L.GetTop() == 0 || L.Get(1) == lua.LNil {
+		L.Push(lua.LNumber(executionTime.Unix()))
+		return 1
+	}
+
+	dateTable := L.CheckTable(1)
+	dateField := func(fieldName string, defaultValue int) int {
+		switch fieldValue := dateTable.RawGetString(fieldName).(type) {
+		case lua.LNumber:
+			return int(fieldValue)
+		case lua.LString:
+			if number, err := strconv.Atoi(strings.TrimSpace(string(fieldValue))); err == nil {
+				return number
+			}
+		}
+		if defaultValue < 0 {
+			L.RaiseError("field '%s' missing in date table", fieldName)
+		}
+		return defaultValue
+	}
+
+	dateTime := time.Date(dateField("year", -1), time.Month(dateField("month", -1)), dateField("day", -1),
+		dateField("hour", 12), dateField("min", 0), dateField("sec", 0), 0, executionTime.Location())
+	L.Push(lua.LNumber(dateTime.Unix()))
+	return 1
+}
+
+// formatLuaDate formats a time with the 'os.date' conversions. Unknown conversions are kept as they are.
+func formatLuaDate(dateTime time.Time, format string) string {
+	var formattedDate strings.Builder
+	for charIndex := 0; charIndex < len(format); charIndex++ {
+		if format[charIndex] != '%' || charIndex == len(format)-1 {
+			formattedDate.WriteByte(format[charIndex])
+			continue
+		}
+
+		charIndex++
+		conversion := format[charIndex]
+		switch {
+		case conversion == '%':
+			formattedDate.WriteByte('%')
+		case conversion == 'w':
+			formattedDate.WriteString(strconv.Itoa(int(dateTime.Weekday())))
+		default:
+			if layout, exists := luaDateFormatsToGo[conversion]; exists == true {
+				formattedDate.WriteString(dateTime.Format(layout))
+			} else {
+				formattedDate.WriteByte('%')
+				formattedDate.WriteByte(conversion)
+			}
+		}
+	}
+
+	return formattedDate.String()
+}
@@ -0,0 +1,174 @@
+package scriptEngine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaOutputSource tells how a Lua output line was written.
+type LuaOutputSource string
+
+const (
+	// LuaOutputSourcePrint is a line written with print(...).
+	LuaOutputSourcePrint LuaOutputSource = "print"
+	// LuaOutputSourceLog is a line written with fenix.log(level, ...).
+	LuaOutputSourceLog LuaOutputSource = "log"
+)
+
+// luaOutputLogLevels are the levels accepted by fenix.log.
+var luaOutputLogLevels = []string{"debug", "info", "warn", "error"}
+
+// LuaOutputLine is one line printed or logged by Lua.
+// Lines written while scripts are loaded have no execution UUID and function name.
+type LuaOutputLine struct {
+	TestCaseExecutionUUID string          `json:"testCaseExecutionUuid,omitempty"`
+	FunctionName          string          `json:"functionName,omitempty"`
+	Source                LuaOutputSource `json:"source"`
+	// Level given to fenix.log, empty for print.
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// LuaOutputLogger receives every line printed or logged by Lua, from all calls.
+type LuaOutputLogger interface {
+	LogLuaOutput(line LuaOutputLine)
+}
+
+var (
+	luaOutputLoggerMutex sync.RWMutex
+	// Lua output goes to the engine logger, see SetLogger, unless another logger is set.
+	luaOutputLogger LuaOutputLogger = slogLuaOutputLogger{}
+)
+
+// SetLuaOutputLogger sets the logger that Lua output is forwarded to. Nil discards the output,
+// it is still returned per call by ExecutePlaceholderWithResult.
+func SetLuaOutputLogger(logger LuaOutputLogger) {
+	luaOutputLoggerMutex.Lock()
+	defer luaOutputLoggerMutex.Unlock()
+
+	luaOutputLogger = logger
+}
+
+// luaOutputSlogLevels maps fenix.log levels to slog levels. Lines from print are logged at debug level.
+var luaOutputSlogLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// slogLuaOutputLogger writes Lua output to the engine logger, tagged with execution UUID, function name and source.
+type slogLuaOutputLogger struct{}
+
+func (slogLuaOutputLogger) LogLuaOutput(line LuaOutputLine) {
+	level, exists := luaOutputSlogLevels[line.Level]
+	if exists == false {
+		level = slog.LevelDebug
+	}
+
+	scriptEngineLogger().Log(context.Background(), level, line.Message,
+		logKeyFunction, line.FunctionName, logKeyExecutionUUID, line.TestCaseExecutionUUID, "source", line.Source)
+}
+
+// luaOutputCapture collects the Lua output of one placeholder call.
+type luaOutputCapture struct {
+	mutex sync.Mutex
+	lines []LuaOutputLine
+}
+
+// append adds a line. Nothing is kept when the input has no capture.
+func (capture *luaOutputCapture) append(line LuaOutputLine) {
+	if capture == nil {
+		return
+	}
+
+	capture.mutex.Lock()
+	capture.lines = append(capture.lines, line)
+	capture.mutex.Unlock()
+}
+
+// snapshot returns a copy of the captured lines.
+func (capture *luaOutputCapture) snapshot() []LuaOutputLine {
+	if capture == nil {
+		return nil
+	}
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	if len(capture.lines) == 0 {
+		return nil
+	}
+
+	return append([]LuaOutputLine{}, capture.lines...)
+}
+
+// writeLuaOutput tags a line with the running call, adds it to the output of the call and forwards it to the logger.
+func writeLuaOutput(L *lua.LState, source LuaOutputSource, level string, message string) {
+	line := LuaOutputLine{Source: source, Level: level, Message: message, Time: time.Now()}
+
+	input := runningFenixLuaCallInput(L)
+	if input != nil {
+		line.TestCaseExecutionUUID = input.TestCaseExecutionUUID
+		line.FunctionName = input.FunctionName
+		input.luaOutput.append(line)
+	}
+
+	luaOutputLoggerMutex.RLock()
+	logger := luaOutputLogger
+	luaOutputLoggerMutex.RUnlock()
+
+	if logger != nil {
+		logger.LogLuaOutput(line)
+	}
+}
+
+// luaOutputMessage joins the arguments from the first index like print does, with tabs.
+func luaOutputMessage(L *lua.LState, firstIndex int) string {
+	top := L.GetTop()
+	if top < firstIndex {
+		return ""
+	}
+
+	parts := make([]string, 0, top-firstIndex+1)
+	for i := firstIndex; i <= top; i++ {
+		parts = append(parts, L.ToStringMeta(L.Get(i)).String())
+	}
+
+	return strings.Join(parts, "\t")
+}
+
+// customPrint replaces the default Lua print function, so output is captured per call instead of written to stdout.
+func customPrint(L *lua.LState) int {
+	writeLuaOutput(L, LuaOutputSourcePrint, "", luaOutputMessage(L, 1))
+	return 0 // Number of results
+}
+
+// fenixLuaLog implements fenix.log(level, ...) with level debug, info, warn or error.
+func fenixLuaLog(L *lua.LState) int {
+	level := L.CheckString(1)
+	if isLuaOutputLogLevel(level) == false {
+		L.ArgError(1, fmt.Sprintf("level must be one of %s", strings.Join(luaOutputLogLevels, ", ")))
+	}
+
+	writeLuaOutput(L, LuaOutputSourceLog, level, luaOutputMessage(L, 2))
+	return 0
+}
+
+// isLuaOutputLogLevel returns true for a level accepted by fenix.log.
+func isLuaOutputLogLevel(level string) bool {
+	for _, logLevel := range luaOutputLogLevels {
+		if logLevel == level {
+			return true
+		}
+	}
+
+	return false
+}
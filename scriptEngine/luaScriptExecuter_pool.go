@@ -0,0 +1,115 @@
+package scriptEngine
+
+import (
+	"sync/atomic"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaStatePoolStats describes the use of the Lua state pool since the engine was initiated.
+type LuaStatePoolStats struct {
+	// Number of Lua states in the pool.
+	Size int
+	// States checked out by a running call.
+	InUse int
+	// Number of check-outs.
+	CheckOuts uint64
+	// Check-outs that had to wait for a free state, and their total wait time.
+	Waits    uint64
+	WaitTime time.Duration
+}
+
+// luaStatePool holds Lua states with the same libraries and scripts loaded. Each call checks out one state,
+// so calls run in parallel up to the pool size.
+type luaStatePool struct {
+	// Free states.
+	states chan *lua.LState
+	// All states, used when the pool is closed.
+	allStates []*lua.LState
+	// Global Lua functions defined by the loaded scripts.
+	functionNames map[string]bool
+	// Instruction budget per placeholder call, 0 means no limit.
+	instructionBudget uint64
+	// All loaded scripts, the domain scripts among them and the options the pool was built with.
+	luaScriptFiles       []LuaScriptsStruct
+	domainLuaScriptFiles []LuaScriptsStruct
+	options              LuaEngineOptions
+	// Functions defined by each script, in load order.
+	scriptLoadReports []LuaScriptLoadReport
+	// Metadata declared by '<FunctionName>_meta' tables.
+	functionMetadata map[string]PlaceholderFunctionMetadata
+
+	inUse     int64
+	checkOuts uint64
+	waits     uint64
+	waitTime  int64
+}
+
+// newLuaStatePool returns a pool with the given states, all free.
+func newLuaStatePool(states []*lua.LState, functionNames map[string]bool, instructionBudget uint64) *luaStatePool {
+	pool := &luaStatePool{
+		states:            make(chan *lua.LState, len(states)),
+		allStates:         states,
+		functionNames:     functionNames,
+		instructionBudget: instructionBudget,
+	}
+	for _, L := range states {
+		pool.states <- L
+	}
+
+	return pool
+}
+
+// checkOut returns a free state, and waits for one when all states are in use.
+func (pool *luaStatePool) checkOut() *lua.LState {
+	var L *lua.LState
+	select {
+	case L = <-pool.states:
+	default:
+		waitStart := time.Now()
+		L = <-pool.states
+		atomic.AddUint64(&pool.waits, 1)
+		atomic.AddInt64(&pool.waitTime, int64(time.Since(waitStart)))
+	}
+	atomic.AddUint64(&pool.checkOuts, 1)
+	atomic.AddInt64(&pool.inUse, 1)
+
+	return L
+}
+
+// checkIn returns a state to the pool.
+func (pool *luaStatePool) checkIn(L *lua.LState) {
+	atomic.AddInt64(&pool.inUse, -1)
+	pool.states <- L
+}
+
+// stats returns the current statistics.
+func (pool *luaStatePool) stats() LuaStatePoolStats {
+	return LuaStatePoolStats{
+		Size:      len(pool.allStates),
+		InUse:     int(atomic.LoadInt64(&pool.inUse)),
+		CheckOuts: atomic.LoadUint64(&pool.checkOuts),
+		Waits:     atomic.LoadUint64(&pool.waits),
+		WaitTime:  time.Duration(atomic.LoadInt64(&pool.waitTime)),
+	}
+}
+
+// close closes all states. The caller makes sure no state is checked out.
+func (pool *luaStatePool) close() {
+	for _, L := range pool.allStates {
+		L.Close()
+	}
+}
+
+// GetLuaStatePoolStats returns the statistics of the Lua state pool. All values are zero when the engine is not initiated.
+func GetLuaStatePoolStats() LuaStatePoolStats {
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
+
+	if luaEngine == nil {
+		return LuaStatePoolStats{}
+	}
+
+	return luaEngine.stats()
+}
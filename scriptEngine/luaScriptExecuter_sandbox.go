@@ -0,0 +1,223 @@
+package scriptEngine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaEngineOptions holds settings for InitiateLuaScriptEngineWithOptions.
+// The zero value gives the unrestricted engine used by InitiateLuaScriptEngine.
+type LuaEngineOptions struct {
+	// Sandbox opens only the allowed libraries, removes 'dofile' and 'loadfile',
+	// limits 'require' to preloaded modules and reduces 'os' to time, date, clock and difftime.
+	Sandbox bool
+	// Libraries opened in the sandbox. Empty means DefaultLuaSandboxLibraries.
+	AllowedLibraries []string
+	// Maximum depth of the Lua call stack. 0 uses the gopher-lua default.
+	CallStackSize int
+	// Maximum number of slots in the Lua data stack (registry). 0 uses the gopher-lua default without growth.
+	RegistryMaxSize int
+	// Maximum number of Lua VM instructions per placeholder call. 0 means no limit.
+	InstructionBudget uint64
+	// Number of Lua states with the same scripts, so that many calls can run at the same time. 0 means 1.
+	PoolSize int
+	// Hashes and public keys that domain scripts must verify against. Nil loads domain scripts without verification.
+	Integrity *LuaScriptIntegrityOptions
+}
+
+// DefaultLuaSandboxLibraries are the libraries opened in the sandbox when no allowlist is given.
+var DefaultLuaSandboxLibraries = []string{"base", "package", "table", "string", "math", "os", "coroutine"}
+
+// luaSandboxLibraries are the libraries that can be allowed in the sandbox, in the order they must be opened.
+var luaSandboxLibraries = []struct {
+	name     string
+	openFunc lua.LGFunction
+}{
+	{name: "package", openFunc: lua.OpenPackage},
+	{name: "base", openFunc: lua.OpenBase},
+	{name: "table", openFunc: lua.OpenTable},
+	{name: "string", openFunc: lua.OpenString},
+	{name: "math", openFunc: lua.OpenMath},
+	{name: "os", openFunc: lua.OpenOs},
+	{name: "coroutine", openFunc: lua.OpenCoroutine},
+}
+
+// luaStandardLibraryNames are the gopher-lua libraries that have a table in the globals and in 'package.loaded'.
+var luaStandardLibraryNames = []string{"table", "io", "os", "string", "math", "debug", "channel", "coroutine"}
+
+// luaSandboxOsFunctions are the only 'os' functions kept in the sandbox.
+var luaSandboxOsFunctions = []string{"time", "date", "clock", "difftime"}
+
+// DefaultLuaSandboxOptions returns a sandbox profile with limits suitable for placeholder functions.
+func DefaultLuaSandboxOptions() LuaEngineOptions {
+	return LuaEngineOptions{
+		Sandbox:           true,
+		AllowedLibraries:  append([]string{}, DefaultLuaSandboxLibraries...),
+		CallStackSize:     200,
+		RegistryMaxSize:   256 * 1024,
+		InstructionBudget: 10000000,
+	}
+}
+
+// validate checks the allowlist, the pool size and the integrity options.
+func (options LuaEngineOptions) validate() error {
+	if options.PoolSize < 0 {
+		return fmt.Errorf("Lua state pool size can't be negative, got %d", options.PoolSize)
+	}
+	for _, libraryName := range options.AllowedLibraries {
+		if isLuaSandboxLibrary(libraryName) == false {
+			return fmt.Errorf("library '%s' can't be opened in the Lua sandbox, allowed libraries are %v", libraryName, DefaultLuaSandboxLibraries)
+		}
+	}
+	// The 'date' and 'fenix' modules and the module scripts are registered in 'package.preload'
+	if options.Sandbox == true && len(options.AllowedLibraries) > 0 && slices.Contains(options.AllowedLibraries, "package") == false {
+		return fmt.Errorf("library 'package' must be allowed in the Lua sandbox, the engine preloads its modules in it")
+	}
+	if options.Integrity != nil {
+		if err := options.Integrity.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// luaStateOptions converts the limits into gopher-lua options. Libraries are always opened by the engine.
+func (options LuaEngineOptions) luaStateOptions() lua.Options {
+	stateOptions := lua.Options{
+		CallStackSize: options.CallStackSize,
+		SkipOpenLibs:  true,
+	}
+	if options.RegistryMaxSize > 0 {
+		stateOptions.RegistrySize = lua.RegistrySize
+		if options.RegistryMaxSize < stateOptions.RegistrySize {
+			stateOptions.RegistrySize = options.RegistryMaxSize
+		}
+		stateOptions.RegistryMaxSize = options.RegistryMaxSize
+	}
+
+	return stateOptions
+}
+
+// isLuaSandboxLibrary returns true when the library can be allowed in the sandbox.
+func isLuaSandboxLibrary(libraryName string) bool {
+	for _, library := range luaSandboxLibraries {
+		if library.name == libraryName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openLuaLibraries opens all standard libraries, or only the allowed ones when sandboxed.
+func openLuaLibraries(L *lua.LState, options LuaEngineOptions) {
+	if options.Sandbox == false {
+		L.OpenLibs()
+		return
+	}
+
+	allowedLibraries := options.AllowedLibraries
+	if len(allowedLibraries) == 0 {
+		allowedLibraries = DefaultLuaSandboxLibraries
+	}
+	allowed := map[string]bool{}
+	for _, libraryName := range allowedLibraries {
+		allowed[libraryName] = true
+	}
+
+	for _, library := range luaSandboxLibraries {
+		if allowed[library.name] == false {
+			continue
+		}
+		L.Push(L.NewFunction(library.openFunc))
+		L.Push(lua.LString(library.name))
+		L.Call(1, 0)
+	}
+
+	// Files can't be loaded from the sandbox
+	L.SetGlobal("dofile", lua.LNil)
+	L.SetGlobal("loadfile", lua.LNil)
+
+	// 'require' only finds modules in 'package.preload'. The first loader is the preload loader,
+	// and 'require' uses the same loader table from the registry, so it is shortened in place.
+	if loaders, ok := L.GetField(L.Get(lua.RegistryIndex), "_LOADERS").(*lua.LTable); ok == true {
+		for loaders.Len() > 1 {
+			loaders.Remove(loaders.Len())
+		}
+	}
+	if packageTable, ok := L.GetGlobal("package").(*lua.LTable); ok == true {
+		L.SetField(packageTable, "path", lua.LString(""))
+		L.SetField(packageTable, "cpath", lua.LString(""))
+	}
+
+	// Libraries that aren't allowed can't be reached with 'require' either
+	loadedTable, _ := L.GetField(L.Get(lua.RegistryIndex), "_LOADED").(*lua.LTable)
+	for _, libraryName := range luaStandardLibraryNames {
+		if allowed[libraryName] == false {
+			L.SetGlobal(libraryName, lua.LNil)
+			if loadedTable != nil {
+				loadedTable.RawSetString(libraryName, lua.LNil)
+			}
+		}
+	}
+
+	// Only the time related 'os' functions are kept, also in the table 'require("os")' returns
+	if osTable, ok := L.GetGlobal("os").(*lua.LTable); ok == true {
+		sandboxOsTable := L.NewTable()
+		for _, functionName := range luaSandboxOsFunctions {
+			sandboxOsTable.RawSetString(functionName, osTable.RawGetString(functionName))
+		}
+		L.SetGlobal("os", sandboxOsTable)
+		if loadedTable != nil {
+			loadedTable.RawSetString("os", sandboxOsTable)
+		}
+	}
+}
+
+// luaInstructionBudgetContext is a context that is done after a number of Lua VM instructions.
+// gopher-lua checks Done() once per instruction while a context is set on the Lua state.
+type luaInstructionBudgetContext struct {
+	context.Context
+	budget           uint64
+	usedInstructions uint64
+}
+
+// exceededChannel is a closed channel returned by Done() when the budget is used up.
+var exceededChannel = func() chan struct{} {
+	channel := make(chan struct{})
+	close(channel)
+	return channel
+}()
+
+func newLuaInstructionBudgetContext(budget uint64) *luaInstructionBudgetContext {
+	return &luaInstructionBudgetContext{Context: context.Background(), budget: budget}
+}
+
+// Done counts one instruction and returns a closed channel when the budget is exceeded.
+// A nil channel is never ready, so the instruction is executed.
+func (budgetContext *luaInstructionBudgetContext) Done() <-chan struct{} {
+	budgetContext.usedInstructions++
+	if budgetContext.exceeded() == true {
+		return exceededChannel
+	}
+
+	return nil
+}
+
+// Err returns the error raised in the Lua state when the budget is exceeded.
+func (budgetContext *luaInstructionBudgetContext) Err() error {
+	if budgetContext.exceeded() == true {
+		return fmt.Errorf("instruction budget of %d exceeded", budgetContext.budget)
+	}
+
+	return nil
+}
+
+// exceeded returns true when more instructions than the budget were used.
+func (budgetContext *luaInstructionBudgetContext) exceeded() bool {
+	return budgetContext.usedInstructions > budgetContext.budget
+}
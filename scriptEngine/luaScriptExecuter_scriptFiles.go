@@ -0,0 +1,210 @@
+package scriptEngine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultLuaScriptPollInterval is used when LuaScriptWatchOptions has no poll interval.
+const defaultLuaScriptPollInterval = 2 * time.Second
+
+// luaModulesDirectory is the directory, directly below the loaded directory, that holds module scripts.
+const luaModulesDirectory = "modules"
+
+// LoadLuaScriptsFromFS reads all '*.lua' files below a directory in a file system, sorted by path.
+// The script name is the path relative to the directory without '.lua', e.g. 'customer/Address'.
+// A '<file>.lua.sig' file next to a script holds its Ed25519 signature, see LuaScriptIntegrityOptions.
+// Files below a 'modules' directory are modules, required with their path below it, e.g. 'modules/customer/address.lua'
+// is required as 'customer.address'.
+func LoadLuaScriptsFromFS(fileSystem fs.FS, directory string) (luaScriptFiles []LuaScriptsStruct, err error) {
+	if directory == "" {
+		directory = "."
+	}
+
+	err = fs.WalkDir(fileSystem, directory, func(filePath string, dirEntry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if dirEntry.IsDir() == true || path.Ext(filePath) != ".lua" {
+			return nil
+		}
+
+		luaScript, err := fs.ReadFile(fileSystem, filePath)
+		if err != nil {
+			return err
+		}
+
+		scriptName := strings.TrimPrefix(strings.TrimPrefix(filePath, directory), "/")
+		if directory == "." {
+			scriptName = filePath
+		}
+		luaScriptFile := LuaScriptsStruct{
+			LuaScriptName: strings.TrimSuffix(scriptName, ".lua"),
+			LuaScript:     luaScript,
+		}
+		signatureFile, err := fs.ReadFile(fileSystem, filePath+luaScriptSignatureExtension)
+		if err == nil {
+			if luaScriptFile.Signature, err = decodeLuaScriptSignature(signatureFile); err != nil {
+				return fmt.Errorf("invalid signature file for '%s': %w", filePath, err)
+			}
+		} else if errors.Is(err, fs.ErrNotExist) == false {
+			return err
+		}
+		if modulePath, isModule := strings.CutPrefix(luaScriptFile.LuaScriptName, luaModulesDirectory+"/"); isModule == true {
+			luaScriptFile.IsModule = true
+			luaScriptFile.ModuleName = strings.ReplaceAll(modulePath, "/", ".")
+		}
+		luaScriptFiles = append(luaScriptFiles, luaScriptFile)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Lua scripts from '%s': %w", directory, err)
+	}
+
+	return luaScriptFiles, nil
+}
+
+// LoadLuaScriptsFromDirectory reads all '*.lua' files below a directory on disk, see LoadLuaScriptsFromFS.
+func LoadLuaScriptsFromDirectory(directory string) ([]LuaScriptsStruct, error) {
+	return LoadLuaScriptsFromFS(os.DirFS(directory), ".")
+}
+
+// ReloadLuaScripts replaces the domain scripts of the running engine, keeping its options.
+// All scripts are loaded into new Lua states first. When a script fails, the running engine is kept and the error
+// is returned. Otherwise the new states are swapped in after running calls are done, and no call is dropped.
+func ReloadLuaScripts(domainLuaScriptFiles []LuaScriptsStruct) error {
+	luaEngineMutex.RLock()
+	if luaEngine == nil {
+		luaEngineMutex.RUnlock()
+		return fmt.Errorf("Lua script engine is not initiated, can't reload Lua scripts")
+	}
+	luaEngineOptions := luaEngine.options
+	luaEngineMutex.RUnlock()
+
+	newLuaEngine, err := buildLuaStatePool(domainLuaScriptFiles, luaEngineOptions, false)
+	if err != nil {
+		newLuaEngine.close()
+		return err
+	}
+
+	swapLuaEngine(newLuaEngine)
+
+	return nil
+}
+
+// LuaScriptWatchOptions holds settings for WatchLuaScripts.
+type LuaScriptWatchOptions struct {
+	// How often the directory is checked for changes. 0 means every 2 seconds.
+	PollInterval time.Duration
+	// Called after every reload attempt, with nil when the new scripts were swapped in.
+	OnReload func(err error)
+}
+
+// LuaScriptWatcher reloads the Lua scripts of a directory when they change.
+type LuaScriptWatcher struct {
+	fileSystem   fs.FS
+	directory    string
+	watchOptions LuaScriptWatchOptions
+	// Fingerprint of the scripts from the last check.
+	fingerprint string
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// WatchLuaScripts polls a directory in a file system and reloads the engine with its '*.lua' files when a file is
+// added, changed or removed. The scripts in the directory replace the domain scripts of the running engine.
+// Failing scripts are reported to OnReload and the previous scripts stay in use until the files change again.
+func WatchLuaScripts(fileSystem fs.FS, directory string, watchOptions LuaScriptWatchOptions) (*LuaScriptWatcher, error) {
+	luaScriptFiles, err := LoadLuaScriptsFromFS(fileSystem, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if watchOptions.PollInterval <= 0 {
+		watchOptions.PollInterval = defaultLuaScriptPollInterval
+	}
+
+	watcher := &LuaScriptWatcher{
+		fileSystem:   fileSystem,
+		directory:    directory,
+		watchOptions: watchOptions,
+		fingerprint:  luaScriptsFingerprint(luaScriptFiles),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go watcher.run()
+
+	return watcher, nil
+}
+
+// Stop ends the watching and waits for a running reload to finish.
+func (watcher *LuaScriptWatcher) Stop() {
+	watcher.stopOnce.Do(func() {
+		close(watcher.stop)
+	})
+	<-watcher.done
+}
+
+// run checks for changes until the watcher is stopped.
+func (watcher *LuaScriptWatcher) run() {
+	defer close(watcher.done)
+
+	ticker := time.NewTicker(watcher.watchOptions.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-watcher.stop:
+			return
+		case <-ticker.C:
+			watcher.reloadWhenChanged()
+		}
+	}
+}
+
+// reloadWhenChanged reloads the scripts when their fingerprint differs from the last check.
+func (watcher *LuaScriptWatcher) reloadWhenChanged() {
+	luaScriptFiles, err := LoadLuaScriptsFromFS(watcher.fileSystem, watcher.directory)
+	if err == nil {
+		fingerprint := luaScriptsFingerprint(luaScriptFiles)
+		if fingerprint == watcher.fingerprint {
+			return
+		}
+		// A failing version is not retried, the next change of the files triggers a new reload
+		watcher.fingerprint = fingerprint
+		err = ReloadLuaScripts(luaScriptFiles)
+	}
+
+	if err != nil {
+		scriptEngineLogger().Error("failed to reload Lua scripts", "directory", watcher.directory, "error", err)
+	} else {
+		scriptEngineLogger().Info("reloaded Lua scripts", "directory", watcher.directory, "scripts", len(luaScriptFiles))
+	}
+	if watcher.watchOptions.OnReload != nil {
+		watcher.watchOptions.OnReload(err)
+	}
+}
+
+// luaScriptsFingerprint returns a hash over the names, contents and signatures of the scripts.
+func luaScriptsFingerprint(luaScriptFiles []LuaScriptsStruct) string {
+	hash := sha256.New()
+	for _, luaScriptFile := range luaScriptFiles {
+		fmt.Fprintf(hash, "%d:%s:%d:", len(luaScriptFile.LuaScriptName), luaScriptFile.LuaScriptName, len(luaScriptFile.LuaScript))
+		hash.Write(luaScriptFile.LuaScript)
+		fmt.Fprintf(hash, ":%d:", len(luaScriptFile.Signature))
+		hash.Write(luaScriptFile.Signature)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
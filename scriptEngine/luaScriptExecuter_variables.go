@@ -1,9 +1,15 @@
 package scriptEngine
 
-import lua "github.com/yuin/gopher-lua"
+import (
+	"sync"
+)
 
 // luaScriptFilesAsByteArray stores all Lua scripts currently loaded by the engine.
 var luaScriptFilesAsByteArray []LuaScriptsStruct
 
-// luaState is the shared gopher-lua VM used for placeholder execution.
-var luaState *lua.LState
+// luaEngine is the pool of Lua states used for placeholder execution, nil when the engine is not initiated.
+var luaEngine *luaStatePool
+
+// luaEngineMutex guards luaEngine. Calls hold the read lock while they use a state from the pool,
+// so initiating or closing the engine waits for running calls.
+var luaEngineMutex sync.RWMutex
@@ -0,0 +1,127 @@
+package scriptEngine
+
+import (
+	"errors"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// scriptVersionProtos returns the prototype of Test_ScriptVersion in every state of the running engine.
+func scriptVersionProtos(t *testing.T) []*lua.FunctionProto {
+	t.Helper()
+
+	luaEngineMutex.RLock()
+	defer luaEngineMutex.RUnlock()
+
+	var protos []*lua.FunctionProto
+	for _, luaState := range luaEngine.allStates {
+		function, isFunction := luaState.GetGlobal("Test_ScriptVersion").(*lua.LFunction)
+		if isFunction == false {
+			t.Fatalf("expected Test_ScriptVersion to be defined in every state")
+		}
+		protos = append(protos, function.Proto)
+	}
+
+	return protos
+}
+
+func isLuaScriptCompiled(luaScript LuaScriptsStruct) bool {
+	compiledLuaScriptsMutex.Lock()
+	defer compiledLuaScriptsMutex.Unlock()
+
+	_, exists := compiledLuaScripts[compiledLuaScriptKey{chunkName: luaScript.LuaScriptName, scriptSHA256: luaScriptSHA256(luaScript.LuaScript)}]
+	return exists
+}
+
+func resetCompiledLuaScripts() {
+	compiledLuaScriptsMutex.Lock()
+	defer compiledLuaScriptsMutex.Unlock()
+
+	compiledLuaScripts = map[compiledLuaScriptKey]*compiledLuaScript{}
+}
+
+func TestCompiledLuaScripts_ShouldShareOnePrototypePerScriptAcrossStatesAndReloads(t *testing.T) {
+	versionOne := LuaScriptsStruct{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = 3
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{versionOne}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	// All pool states run the same compiled function
+	protos := scriptVersionProtos(t)
+	t.Logf("Output [pool-protos]\n  Protos: %p", protos)
+	for _, proto := range protos[1:] {
+		if proto != protos[0] {
+			t.Fatalf("expected every state to share one prototype, got %p", protos)
+		}
+	}
+
+	// Reloading the same script doesn't compile it again
+	if err := ReloadLuaScripts([]LuaScriptsStruct{versionOne}); err != nil {
+		t.Fatalf("failed to reload Lua scripts: %v", err)
+	}
+	if reloadedProtos := scriptVersionProtos(t); reloadedProtos[0] != protos[0] {
+		t.Fatalf("expected reload to reuse the compiled script")
+	}
+
+	// A changed script is compiled again and the earlier version is dropped
+	versionTwo := LuaScriptsStruct{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v2"))}
+	if err := ReloadLuaScripts([]LuaScriptsStruct{versionTwo}); err != nil {
+		t.Fatalf("failed to reload Lua scripts: %v", err)
+	}
+	if value := executeScriptVersion(t, "after-changed-reload"); value != "v2" {
+		t.Fatalf("expected v2 after reload, got %q", value)
+	}
+	if isLuaScriptCompiled(versionOne) == true || isLuaScriptCompiled(versionTwo) == false {
+		t.Fatalf("expected only the running version of the script to be kept compiled")
+	}
+}
+
+func TestCompiledLuaScripts_ShouldNotKeepScriptsThatFailToCompile(t *testing.T) {
+	brokenScript := LuaScriptsStruct{LuaScriptName: "brokenCompiled", LuaScript: []byte("local a = 1\nlocal b = = 2\n")}
+
+	// The second load reports the same compile error as the first
+	for _, callLabel := range []string{"first-load", "second-load"} {
+		err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{brokenScript}, DefaultLuaSandboxOptions())
+		logLuaScriptLoadErrors(t, callLabel, err)
+
+		var loadErrors LuaScriptLoadErrors
+		if errors.As(err, &loadErrors) == false || loadErrors[0].Phase != LuaScriptLoadPhaseCompile || loadErrors[0].Line != 2 {
+			t.Fatalf("expected compile error at line 2, got: %v", err)
+		}
+	}
+	if isLuaScriptCompiled(brokenScript) == true {
+		t.Fatalf("expected failing script not to be kept")
+	}
+}
+
+// BenchmarkInitiateLuaScriptEngine measures the startup of an engine with a pool of four states, with the scripts
+// compiled by an earlier start and with every script compiled again.
+func BenchmarkInitiateLuaScriptEngine(b *testing.B) {
+	luaScriptFiles := []LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}}
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = 4
+	defer CloseDownLuaScriptEngine()
+
+	for _, benchmarkCase := range []struct {
+		name         string
+		resetCompile bool
+	}{
+		{name: "compiled-once"},
+		{name: "compiled-every-start", resetCompile: true},
+	} {
+		b.Run(benchmarkCase.name, func(b *testing.B) {
+			for b.Loop() {
+				if benchmarkCase.resetCompile == true {
+					resetCompiledLuaScripts()
+				}
+				if err := InitiateLuaScriptEngineWithOptions(luaScriptFiles, luaEngineOptions); err != nil {
+					b.Fatalf("failed to initiate Lua engine: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package scriptEngine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// fenixModuleTestLuaScript implements Lua functions with the 'fenix' module that mirror the Go handlers.
+const fenixModuleTestLuaScript = `
+local fenix = require("fenix")
+
+-- Pure helpers can be used while the script is loaded
+local loadTimeValue = fenix.format_decimal(fenix.round(12.345, 2), 2, 4, 3, ",")
+
+function Test_FenixDecimal(inputTable)
+    local arrayIndexes = inputTable[2]
+    return { success = true, value = fenix.random_decimal(tonumber(arrayIndexes[1]), 3, 2, 5, 3, "."), errorMessage = "" }
+end
+
+function Test_FenixUniqueId(inputTable)
+    local functionArgs = inputTable[3]
+    return { success = true, value = fenix.controlled_unique_id(functionArgs[1], 2), errorMessage = "" }
+end
+
+function Test_FenixClock(inputTable)
+    return { success = true, value = {
+        now = fenix.now(),
+        date = fenix.now("2006-01-02"),
+        time = fenix.time(),
+        tomorrow = fenix.today_shift_day(1),
+    }, errorMessage = "" }
+end
+
+function Test_FenixTestData(inputTable)
+    local missing = fenix.testdata("Missing")
+    return { success = true, value = fenix.testdata("AccountNumber") .. "/" .. tostring(missing), errorMessage = "" }
+end
+
+function Test_FenixRng(inputTable)
+    local rng = fenix.rng(3)
+    local values = {}
+    for i = 1, 5 do values[i] = rng:int(1, 6) end
+    return { success = true, value = { values = values, float = fenix.rng(3):float() }, errorMessage = "" }
+end
+
+function Test_FenixLoadTime(inputTable)
+    return { success = true, value = loadTimeValue .. "/" .. fenix.pad("7.5", 3, 2), errorMessage = "" }
+end
+
+function Test_FenixBadArgument(inputTable)
+    return { success = true, value = fenix.random_decimal(1, 3, 2, 5, 3, ".."), errorMessage = "" }
+end
+`
+
+func initiateFenixModuleTestEngine(t *testing.T) {
+	t.Helper()
+
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "fenixModuleTest", LuaScript: []byte(fenixModuleTestLuaScript)}},
+		DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+}
+
+func executeFenixModuleRequest(t *testing.T, callLabel string, request PlaceholderExecutionRequest) PlaceholderValue {
+	t.Helper()
+
+	logExecutionRequest(t, callLabel, request)
+	value, err := ExecutePlaceholderValue(request)
+	logPlaceholderExecutionResult(t, callLabel, value.Format(), err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	return value
+}
+
+func TestFenixLuaModule_ShouldMatchGoHandlersForSameEntropy(t *testing.T) {
+	initiateFenixModuleTestEngine(t)
+	defer CloseDownLuaScriptEngine()
+
+	clockTime := time.Date(2024, 3, 15, 10, 20, 30, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		luaRequest   PlaceholderExecutionRequest
+		goRequest    PlaceholderExecutionRequest
+		expectLength int
+	}{
+		{
+			name: "random-decimal",
+			luaRequest: PlaceholderExecutionRequest{
+				FunctionName: "Test_FenixDecimal", ArrayIndexes: []int{2},
+				UseEntropyFromExecutionUUID: true, ExtraEntropy: 5, TestCaseExecutionUUID: "fenix-module-uuid",
+			},
+			goRequest: PlaceholderExecutionRequest{
+				FunctionName: "Fenix_RandomPositiveDecimalValue", ArrayIndexes: []int{2}, Arguments: []string{"3", "2", "5", "3", "."},
+				UseEntropyFromExecutionUUID: true, ExtraEntropy: 5, TestCaseExecutionUUID: "fenix-module-uuid",
+			},
+			expectLength: len("00000.000"),
+		},
+		{
+			name: "controlled-unique-id",
+			luaRequest: PlaceholderExecutionRequest{
+				FunctionName: "Test_FenixUniqueId", Arguments: []string{"ID-%YYYYMMDD%-%n(6)%-%A(4)%"},
+				UseEntropyFromExecutionUUID: true, ExtraEntropy: 7, TestCaseExecutionUUID: "fenix-module-uuid", ClockTime: clockTime,
+			},
+			goRequest: PlaceholderExecutionRequest{
+				FunctionName: "Fenix_ControlledUniqueId", ArrayIndexes: []int{2}, Arguments: []string{"ID-%YYYYMMDD%-%n(6)%-%A(4)%", "true", "7"},
+				TestCaseExecutionUUID: "fenix-module-uuid", ClockTime: clockTime,
+			},
+			expectLength: len("ID-20240315-123456-ABCD"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			luaValue := executeFenixModuleRequest(t, testCase.name+"-lua", testCase.luaRequest).Format()
+			goValue := executeFenixModuleRequest(t, testCase.name+"-go", testCase.goRequest).Format()
+			if luaValue != goValue || len(luaValue) != testCase.expectLength {
+				t.Fatalf("expected Lua value %q to equal Go value %q", luaValue, goValue)
+			}
+		})
+	}
+}
+
+func TestFenixLuaModule_ShouldUseExecutionClockAndTestData(t *testing.T) {
+	initiateFenixModuleTestEngine(t)
+	defer CloseDownLuaScriptEngine()
+
+	clockTime := time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC)
+	clockValue := executeFenixModuleRequest(t, "clock", PlaceholderExecutionRequest{FunctionName: "Test_FenixClock", ClockTime: clockTime})
+	expectedFields := map[string]string{
+		"now":      "2024-12-31T23:30:00Z",
+		"date":     "2024-12-31",
+		"time":     "1735687800",
+		"tomorrow": "2025-01-01",
+	}
+	for fieldName, expectedValue := range expectedFields {
+		fieldValue, err := clockValue.Field(fieldName)
+		if err != nil || fieldValue.Format() != expectedValue {
+			t.Fatalf("expected %s to be %q, got %q (%v)", fieldName, expectedValue, fieldValue.Format(), err)
+		}
+	}
+
+	testDataValue := executeFenixModuleRequest(t, "testdata", PlaceholderExecutionRequest{
+		FunctionName:   "Test_FenixTestData",
+		TestDataValues: map[string]string{"AccountNumber": "1234-5678"},
+	})
+	if testDataValue.Format() != "1234-5678/nil" {
+		t.Fatalf("unexpected TestData value: %q", testDataValue.Format())
+	}
+}
+
+func TestFenixLuaModule_ShouldGiveDeterministicRngAndLoadTimeHelpers(t *testing.T) {
+	initiateFenixModuleTestEngine(t)
+	defer CloseDownLuaScriptEngine()
+
+	request := PlaceholderExecutionRequest{FunctionName: "Test_FenixRng", UseEntropyFromExecutionUUID: true, TestCaseExecutionUUID: "fenix-rng-uuid"}
+	firstValue := executeFenixModuleRequest(t, "rng-first", request).Format()
+	secondValue := executeFenixModuleRequest(t, "rng-second", request).Format()
+	if firstValue != secondValue {
+		t.Fatalf("expected same values for same entropy, got %q and %q", firstValue, secondValue)
+	}
+
+	otherValue := executeFenixModuleRequest(t, "rng-other-uuid", PlaceholderExecutionRequest{
+		FunctionName: "Test_FenixRng", UseEntropyFromExecutionUUID: true, TestCaseExecutionUUID: "fenix-rng-other-uuid",
+	}).Format()
+	if otherValue == firstValue {
+		t.Fatalf("expected other values for other entropy, got %q", otherValue)
+	}
+
+	if value := executeFenixModuleRequest(t, "load-time", PlaceholderExecutionRequest{FunctionName: "Test_FenixLoadTime"}).Format(); value != "0012,350/007.50" {
+		t.Fatalf("unexpected load time helper value: %q", value)
+	}
+}
+
+func TestFenixLuaModule_ShouldRaiseLuaErrors(t *testing.T) {
+	initiateFenixModuleTestEngine(t)
+	defer CloseDownLuaScriptEngine()
+
+	_, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_FenixBadArgument"})
+	logPlaceholderExecutionResult(t, "bad-argument", "", err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindLuaRuntime || strings.Contains(err.Error(), "decimalPointCharacter must be a single character") == false {
+		t.Fatalf("expected Lua runtime error for bad argument, got: %v", err)
+	}
+
+	err = InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "fenixAtLoadTime", LuaScript: []byte(`local seed = require("fenix").entropy()`)},
+	}, DefaultLuaSandboxOptions())
+	t.Logf("Output [load-time-entropy]\n  Error: %v", err)
+	if err == nil || strings.Contains(err.Error(), "fenix.entropy can only be used during a placeholder call") == false {
+		t.Fatalf("expected load error for call dependent function, got: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// mathRandomLuaScript draws values after seeding with the first argument.
+const mathRandomLuaScript = `
+function Test_SeededRandom(inputTable)
+    math.randomseed(tonumber(inputTable[3][1]))
+    return { success = true, value = string.format("%.12f %d %d", math.random(), math.random(6), math.random(10, 20)), errorMessage = "" }
+end
+`
+
+func TestLuaMathRandom_ShouldGiveTheSameValuesForTheSameSeed(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = 2
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "mathRandom", LuaScript: []byte(mathRandomLuaScript)}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	// The values are the ones of Go's generator with the same seed, like the Go placeholder functions use
+	randomGenerator := rand.New(rand.NewSource(42))
+	expectedValue := fmt.Sprintf("%.12f %d %d", randomGenerator.Float64(), randomGenerator.Intn(6)+1, randomGenerator.Intn(11)+10)
+
+	for _, callLabel := range []string{"first-call", "second-call", "third-call"} {
+		value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_SeededRandom", Arguments: []string{"42"}})
+		logPlaceholderExecutionResult(t, callLabel, value, err)
+		if err != nil || value != expectedValue {
+			t.Fatalf("expected %q, got %q (%v)", expectedValue, value, err)
+		}
+	}
+}
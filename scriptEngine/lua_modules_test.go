@@ -0,0 +1,151 @@
+package scriptEngine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// formatModuleLuaScript is a helper module, it counts how often it was run.
+const formatModuleLuaScript = `
+local format = {}
+
+moduleRunCount = (moduleRunCount or 0) + 1
+
+function format.upper(text)
+    return string.upper(text)
+end
+
+return format
+`
+
+// moduleUserLuaScript returns a script that requires the format module in the function it defines.
+func moduleUserLuaScript(functionName string) string {
+	return `
+function ` + functionName + `(inputTable)
+    local format = require("shared.format")
+    return { success = true, value = format.upper(inputTable[3][1]) .. ":" .. moduleRunCount, errorMessage = "" }
+end
+`
+}
+
+func TestInitiateLuaScriptEngine_ShouldRequireModulesLazilyAndOnce(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "customer", LuaScript: []byte(moduleUserLuaScript("Test_ModuleCustomer"))},
+		{LuaScriptName: "order", LuaScript: []byte(moduleUserLuaScript("Test_ModuleOrder"))},
+		{LuaScriptName: "shared/format", LuaScript: []byte(formatModuleLuaScript), IsModule: true, ModuleName: "shared.format"},
+	}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	// Both scripts share the module, and it is only run once per Lua state
+	for _, functionName := range []string{"Test_ModuleCustomer", "Test_ModuleOrder"} {
+		value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: functionName, Arguments: []string{"abc"}})
+		logPlaceholderExecutionResult(t, functionName, value, err)
+		if err != nil || value != "ABC:1" {
+			t.Fatalf("expected 'ABC:1', got %q (%v)", value, err)
+		}
+	}
+
+	// Modules are not run when loaded, so they define no functions
+	report := GetLuaScriptLoadReport()
+	moduleReport := report[len(report)-1]
+	t.Logf("Output [module-report]\n  Report: %+v", moduleReport)
+	if moduleReport.ScriptName != "shared/format" || moduleReport.ModuleName != "shared.format" || moduleReport.DefinedFunctions != nil {
+		t.Fatalf("unexpected module report: %+v", moduleReport)
+	}
+}
+
+func TestInitiateLuaScriptEngine_ShouldReportMissingModuleWithScriptNameAndLine(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "customer", LuaScript: []byte("local a = 1\nlocal format = require(\"shared.missing\")\n")},
+		{LuaScriptName: "lazy", LuaScript: []byte("function Test_Lazy()\n    return require('shared.other')\nend\n")},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "missing-module", err)
+
+	// The run error of 'customer' only repeats the missing module, it is not reported
+	var loadErrors LuaScriptLoadErrors
+	if errors.As(err, &loadErrors) == false || len(loadErrors) != 2 {
+		t.Fatalf("expected two load errors, got: %v", err)
+	}
+	if loadErrors[0].ScriptName != "customer" || loadErrors[0].Phase != LuaScriptLoadPhaseDependency || loadErrors[0].Line != 2 {
+		t.Fatalf("unexpected load error: %+v", loadErrors[0])
+	}
+	if loadErrors[1].ScriptName != "lazy" || loadErrors[1].Line != 2 {
+		t.Fatalf("expected missing module in function body to be reported, got: %+v", loadErrors[1])
+	}
+	if strings.Contains(err.Error(),
+		"Lua script 'customer' has a dependency error at line 2: requires module 'shared.missing', which is not registered") == false {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
+
+func TestInitiateLuaScriptEngine_ShouldReportModuleCyclesAndDuplicateNames(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "a", LuaScript: []byte("local b = require('b')\nreturn {}"), IsModule: true},
+		{LuaScriptName: "b", LuaScript: []byte("local a = require('a')\nreturn {}"), IsModule: true},
+		{LuaScriptName: "c", LuaScript: []byte("return { a = function() return require('c') end }"), IsModule: true},
+		{LuaScriptName: "format", LuaScript: []byte("return {}"), IsModule: true, ModuleName: "c"},
+		{LuaScriptName: "myDate", LuaScript: []byte("return {}"), IsModule: true, ModuleName: "date"},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "cycles-and-duplicates", err)
+
+	var loadErrors LuaScriptLoadErrors
+	if errors.As(err, &loadErrors) == false || len(loadErrors) != 3 {
+		t.Fatalf("expected three load errors, got: %v", err)
+	}
+	expectedMessages := []string{
+		"module name 'c' is also used by script 'c'",
+		"module name 'date' is used by a built-in module",
+		"module 'a' requires itself when it runs: a -> b -> a",
+	}
+	for errorIndex, expectedMessage := range expectedMessages {
+		if loadErrors[errorIndex].Phase != LuaScriptLoadPhaseDependency || loadErrors[errorIndex].Message != expectedMessage {
+			t.Fatalf("expected %q, got: %+v", expectedMessage, loadErrors[errorIndex])
+		}
+	}
+}
+
+func TestInitiateLuaScriptEngine_ShouldReportModuleSyntaxErrorWhenLoaded(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "shared/format", LuaScript: []byte("local format = {}\nreturn = format\n"), IsModule: true},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "module-syntax-error", err)
+
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false || loadError.ScriptName != "shared/format" ||
+		loadError.Phase != LuaScriptLoadPhaseCompile || loadError.Line != 2 {
+		t.Fatalf("expected compile error at line 2, got: %v", err)
+	}
+}
+
+func TestLoadLuaScriptsFromFS_ShouldLoadModulesDirectoryAsModules(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"scripts/customer.lua":              {Data: []byte(moduleUserLuaScript("Test_ModuleCustomer"))},
+		"scripts/modules/shared/format.lua": {Data: []byte(formatModuleLuaScript)},
+	}
+
+	luaScriptFiles, err := LoadLuaScriptsFromFS(fileSystem, "scripts")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Logf("Output [load-modules-from-fs]\n  Scripts: %d", len(luaScriptFiles))
+	if len(luaScriptFiles) != 2 || luaScriptFiles[0].IsModule == true ||
+		luaScriptFiles[1].IsModule == false || luaScriptFiles[1].ModuleName != "shared.format" {
+		t.Fatalf("unexpected scripts: %+v", luaScriptFiles)
+	}
+
+	if err = InitiateLuaScriptEngineWithOptions(luaScriptFiles, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ModuleCustomer", Arguments: []string{"fs"}})
+	logPlaceholderExecutionResult(t, "module-from-fs", value, err)
+	if err != nil || value != "FS:1" {
+		t.Fatalf("expected 'FS:1', got %q (%v)", value, err)
+	}
+}
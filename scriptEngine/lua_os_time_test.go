@@ -0,0 +1,71 @@
+package scriptEngine
+
+import (
+	"testing"
+	"time"
+)
+
+// osTimeTestLuaScript reads the clock with the standard 'os' functions and the 'date' module.
+const osTimeTestLuaScript = `
+local date = require("date")
+
+function Test_OsTime(inputTable)
+    local dateTable = os.date("*t")
+    return { success = true, value = {
+        date = os.date("%Y-%m-%d %H:%M:%S"),
+        utc = os.date("!%H:%M"),
+        time = tostring(os.time()),
+        tableTime = tostring(os.time({ year = dateTable.year, month = dateTable.month, day = dateTable.day, hour = dateTable.hour, min = dateTable.min, sec = dateTable.sec })),
+        weekday = tostring(dateTable.wday),
+        dateModule = date():fmt("%Y-%m-%d"),
+    }, errorMessage = "" }
+end
+`
+
+func TestLuaOsTime_ShouldFollowExecutionClockAndTimeZone(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "osTimeTest", LuaScript: []byte(osTimeTestLuaScript)}},
+		DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	clockTime := time.Date(2024, 12, 31, 23, 30, 15, 0, time.FixedZone("UTC+2", 2*60*60))
+	request := PlaceholderExecutionRequest{FunctionName: "Test_OsTime", ClockTime: clockTime}
+	logExecutionRequest(t, "os-time", request)
+	value, err := ExecutePlaceholderValue(request)
+	logPlaceholderExecutionResult(t, "os-time", value.Format(), err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expectedFields := map[string]string{
+		"date":       "2024-12-31 23:30:15",
+		"utc":        "21:30",
+		"time":       "1735680615",
+		"tableTime":  "1735680615",
+		"weekday":    "3",
+		"dateModule": "2024-12-31",
+	}
+	for fieldName, expectedValue := range expectedFields {
+		fieldValue, err := value.Field(fieldName)
+		if err != nil || fieldValue.Format() != expectedValue {
+			t.Fatalf("expected %s to be %q, got %q (%v)", fieldName, expectedValue, fieldValue.Format(), err)
+		}
+	}
+}
+
+func TestLuaOsTime_HappyLuaTimeShouldUseExecutionClock(t *testing.T) {
+	if err := InitiateLuaScriptEngine([]LuaScriptsStruct{}); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	request := PlaceholderExecutionRequest{FunctionName: "HappyLuaTime", ClockTime: time.Date(2025, 6, 1, 7, 8, 9, 0, time.UTC)}
+	logExecutionRequest(t, "happy-lua-time-clock", request)
+	value, err := ExecutePlaceholderValue(request)
+	logPlaceholderExecutionResult(t, "happy-lua-time-clock", value.Format(), err)
+	if err != nil || value.Format() != "My name is Lua and the time is 07:08:09" {
+		t.Fatalf("expected the time of the execution clock, got %q (%v)", value.Format(), err)
+	}
+}
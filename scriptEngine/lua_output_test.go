@@ -0,0 +1,191 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// outputTestLuaScript prints and logs during calls and while it is loaded.
+const outputTestLuaScript = `
+local fenix = require("fenix")
+
+print("loading outputTest")
+
+function Test_LuaOutput(inputTable)
+    local functionArgs = inputTable[3]
+    print("value is", functionArgs[1])
+    fenix.log("warn", "almost done", 42)
+    return { success = true, value = functionArgs[1], errorMessage = "" }
+end
+
+function Test_LuaOutputFailing(inputTable)
+    print("before failure")
+    error("failing on purpose")
+end
+
+function Test_LuaOutputBadLevel(inputTable)
+    fenix.log("loud", "not a level")
+    return { success = true, value = "", errorMessage = "" }
+end
+`
+
+// recordingLuaOutputLogger keeps every forwarded line.
+type recordingLuaOutputLogger struct {
+	mutex sync.Mutex
+	lines []LuaOutputLine
+}
+
+func (logger *recordingLuaOutputLogger) LogLuaOutput(line LuaOutputLine) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	logger.lines = append(logger.lines, line)
+}
+
+func logLuaOutputLines(t *testing.T, callLabel string, lines []LuaOutputLine) {
+	t.Helper()
+	t.Logf("Lua output [%s]\n  Lines: %d", callLabel, len(lines))
+	for _, line := range lines {
+		t.Logf("  %s %s %s %s: %q", line.TestCaseExecutionUUID, line.FunctionName, line.Source, line.Level, line.Message)
+	}
+}
+
+func initiateLuaOutputTestEngine(t *testing.T, poolSize int) *recordingLuaOutputLogger {
+	t.Helper()
+
+	logger := &recordingLuaOutputLogger{}
+	SetLuaOutputLogger(logger)
+	t.Cleanup(func() { SetLuaOutputLogger(slogLuaOutputLogger{}) })
+
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = poolSize
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "outputTest", LuaScript: []byte(outputTestLuaScript)}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	t.Cleanup(CloseDownLuaScriptEngine)
+
+	return logger
+}
+
+func TestLuaOutput_ShouldCapturePrintAndLogPerCall(t *testing.T) {
+	logger := initiateLuaOutputTestEngine(t, 1)
+
+	request := PlaceholderExecutionRequest{FunctionName: "Test_LuaOutput", Arguments: []string{"abc"}, TestCaseExecutionUUID: "lua-output-execution"}
+	logExecutionRequest(t, "print-and-log", request)
+	result, err := ExecutePlaceholderWithResult(request)
+	logPlaceholderExecutionResult(t, "print-and-log", result.Value.Format(), err)
+	logLuaOutputLines(t, "print-and-log", result.LuaOutput)
+	if err != nil || result.Value.Format() != "abc" {
+		t.Fatalf("expected 'abc', got %q (%v)", result.Value.Format(), err)
+	}
+
+	expectedLines := []LuaOutputLine{
+		{TestCaseExecutionUUID: "lua-output-execution", FunctionName: "Test_LuaOutput", Source: LuaOutputSourcePrint, Message: "value is\tabc"},
+		{TestCaseExecutionUUID: "lua-output-execution", FunctionName: "Test_LuaOutput", Source: LuaOutputSourceLog, Level: "warn", Message: "almost done\t42"},
+	}
+	if len(result.LuaOutput) != len(expectedLines) {
+		t.Fatalf("expected %d lines, got %d", len(expectedLines), len(result.LuaOutput))
+	}
+	for lineIndex, expectedLine := range expectedLines {
+		line := result.LuaOutput[lineIndex]
+		if line.Time.IsZero() == true {
+			t.Fatalf("expected line %d to have a time", lineIndex)
+		}
+		line.Time = expectedLine.Time
+		if line != expectedLine {
+			t.Fatalf("expected line %d to be %+v, got %+v", lineIndex, expectedLine, line)
+		}
+	}
+
+	// The logger also gets the lines printed while the scripts were loaded, without call tags
+	logger.mutex.Lock()
+	forwardedLines := append([]LuaOutputLine{}, logger.lines...)
+	logger.mutex.Unlock()
+	logLuaOutputLines(t, "forwarded", forwardedLines)
+	loadTimeLineForwarded := false
+	for _, line := range forwardedLines {
+		if line.Message == "loading outputTest" && line.FunctionName == "" {
+			loadTimeLineForwarded = true
+		}
+	}
+	if loadTimeLineForwarded == false || len(forwardedLines) < 2 || forwardedLines[len(forwardedLines)-1].Message != "almost done\t42" {
+		t.Fatalf("expected the load time line and the call lines to be forwarded, got %+v", forwardedLines)
+	}
+
+	// Go handlers have no Lua output
+	result, err = ExecutePlaceholderWithResult(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}})
+	if err != nil || result.LuaOutput != nil {
+		t.Fatalf("expected no Lua output from a Go handler, got %+v (%v)", result.LuaOutput, err)
+	}
+}
+
+func TestLuaOutput_ShouldReturnOutputOfFailingCalls(t *testing.T) {
+	initiateLuaOutputTestEngine(t, 1)
+
+	result, err := ExecutePlaceholderWithResult(PlaceholderExecutionRequest{FunctionName: "Test_LuaOutputFailing"})
+	logPlaceholderExecutionResult(t, "failing", "", err)
+	logLuaOutputLines(t, "failing", result.LuaOutput)
+	if err == nil || len(result.LuaOutput) != 1 || result.LuaOutput[0].Message != "before failure" {
+		t.Fatalf("expected error with the printed line, got %+v (%v)", result.LuaOutput, err)
+	}
+
+	_, err = ExecutePlaceholderWithResult(PlaceholderExecutionRequest{FunctionName: "Test_LuaOutputBadLevel"})
+	logPlaceholderExecutionResult(t, "bad-level", "", err)
+	if err == nil || strings.Contains(err.Error(), "level must be one of debug, info, warn, error") == false {
+		t.Fatalf("expected invalid level error, got: %v", err)
+	}
+}
+
+func TestLuaOutput_ShouldNotMixOutputOfConcurrentCalls(t *testing.T) {
+	initiateLuaOutputTestEngine(t, 4)
+	SetLuaOutputLogger(nil)
+
+	var waitGroup sync.WaitGroup
+	errs := make(chan error, 40)
+	for callIndex := 0; callIndex < 40; callIndex++ {
+		waitGroup.Add(1)
+		go func(callIndex int) {
+			defer waitGroup.Done()
+
+			testCaseExecutionUUID := fmt.Sprintf("lua-output-execution-%d", callIndex)
+			argument := fmt.Sprintf("call-%d", callIndex)
+			result, err := ExecutePlaceholderWithResult(PlaceholderExecutionRequest{
+				FunctionName: "Test_LuaOutput", Arguments: []string{argument}, TestCaseExecutionUUID: testCaseExecutionUUID})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(result.LuaOutput) != 2 || result.LuaOutput[0].Message != "value is\t"+argument ||
+				result.LuaOutput[0].TestCaseExecutionUUID != testCaseExecutionUUID || result.LuaOutput[1].TestCaseExecutionUUID != testCaseExecutionUUID {
+				errs <- fmt.Errorf("call %d got output of another call: %+v", callIndex, result.LuaOutput)
+			}
+		}(callIndex)
+	}
+	waitGroup.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected result: %v", err)
+	}
+}
+
+func TestLuaOutput_ShouldBeAddedToAuditRecords(t *testing.T) {
+	initiateLuaOutputTestEngine(t, 1)
+	sink := NewInMemoryPlaceholderAuditSink()
+	SetPlaceholderAuditSink(sink)
+	defer SetPlaceholderAuditSink(nil)
+
+	if _, err := ExecutePlaceholder(PlaceholderExecutionRequest{
+		FunctionName: "Test_LuaOutput", Arguments: []string{"audited"}, TestCaseExecutionUUID: "lua-output-audit"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	records, err := GetPlaceholderAuditRecords("lua-output-audit")
+	logPlaceholderAuditRecords(t, "audit", records)
+	if err != nil || len(records) != 1 || len(records[0].LuaOutput) != 2 || records[0].LuaOutput[0].Message != "value is\taudited" {
+		t.Fatalf("expected one record with the Lua output, got %+v (%v)", records, err)
+	}
+}
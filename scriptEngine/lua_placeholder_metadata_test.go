@@ -0,0 +1,149 @@
+package scriptEngine
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// metadataTestLuaScript declares metadata next to one function and leaves another without.
+const metadataTestLuaScript = `
+Test_LuaMetadataPad_meta = {
+    description = "Pads a number with zeros.",
+    args = {
+        { name = "Value", type = "integer", description = "Number to pad." },
+        { name = "Width", type = "integer", optional = true },
+    },
+    array_indexes = "none",
+    examples = { "{{Test.LuaMetadataPad(7, 3)}}" },
+}
+
+function Test_LuaMetadataPad(inputTable)
+    local functionArgs = inputTable[3]
+    local width = tonumber(functionArgs[2] or "2")
+    return { success = true, value = string.format("%0" .. width .. "d", tonumber(functionArgs[1])), errorMessage = "" }
+end
+
+function Test_LuaWithoutMetadata(inputTable)
+    return { success = true, value = "no metadata", errorMessage = "" }
+end
+`
+
+func TestLuaPlaceholderMetadata_ShouldBeReadAtLoadTimeAndListed(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "metadataTest", LuaScript: []byte(metadataTestLuaScript)}},
+		DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	metadata, exists := DescribePlaceholderFunction("Test_LuaMetadataPad", "")
+	t.Logf("Output [describe-lua-function]\n  Metadata: %+v", metadata)
+	expectedMetadata := PlaceholderFunctionMetadata{
+		FunctionName: "Test_LuaMetadataPad",
+		Runtime:      PlaceholderRuntimeLua,
+		Description:  "Pads a number with zeros.",
+		Arguments: []PlaceholderArgumentMetadata{
+			{Name: "Value", Type: PlaceholderArgumentTypeInteger, Description: "Number to pad."},
+			{Name: "Width", Type: PlaceholderArgumentTypeInteger, Optional: true},
+		},
+		ArrayIndexes: PlaceholderArrayIndexesNone,
+		Examples:     []string{"{{Test.LuaMetadataPad(7, 3)}}"},
+		Declared:     true,
+	}
+	if exists == false || reflect.DeepEqual(metadata, expectedMetadata) == false {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+
+	listedFunctions := map[string]PlaceholderFunctionMetadata{}
+	for _, listedMetadata := range ListPlaceholderFunctions() {
+		listedFunctions[string(listedMetadata.Runtime)+":"+listedMetadata.FunctionName] = listedMetadata
+	}
+	if listedFunctions["lua:Test_LuaWithoutMetadata"].Declared == true || listedFunctions["lua:Test_LuaMetadataPad"].Declared == false ||
+		listedFunctions["lua:HappyLuaTime"].Declared == false || listedFunctions["go:Fenix_TodayShiftDay"].Declared == false {
+		t.Fatalf("expected Go handlers and Lua functions in the list, got: %v", listedFunctions)
+	}
+	if _, exists = listedFunctions["lua:pcall"]; exists == true {
+		t.Fatalf("expected Lua standard library functions not to be listed")
+	}
+}
+
+func TestLuaPlaceholderMetadata_ShouldValidateLuaCallsLikeGoCalls(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "metadataTest", LuaScript: []byte(metadataTestLuaScript)}},
+		DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	testCases := []struct {
+		name          string
+		request       PlaceholderExecutionRequest
+		expectedValue string
+		expectedError string
+	}{
+		{name: "valid", request: PlaceholderExecutionRequest{FunctionName: "Test_LuaMetadataPad", Arguments: []string{"7", "3"}}, expectedValue: "007"},
+		{name: "wrong-type", request: PlaceholderExecutionRequest{FunctionName: "Test_LuaMetadataPad", Arguments: []string{"seven"}},
+			expectedError: "argument 1 ('Value') must be of type integer"},
+		{name: "array-index", request: PlaceholderExecutionRequest{FunctionName: "Test_LuaMetadataPad", ArrayIndexes: []int{1}, Arguments: []string{"7"}},
+			expectedError: "does not support array indexes"},
+		{name: "happy-lua-time-argument", request: PlaceholderExecutionRequest{FunctionName: "HappyLuaTime", Arguments: []string{"1"}},
+			expectedError: "takes 0 arguments, got 1"},
+		{name: "without-metadata", request: PlaceholderExecutionRequest{FunctionName: "Test_LuaWithoutMetadata", ArrayIndexes: []int{1, 2}, Arguments: []string{"x"}},
+			expectedValue: "no metadata"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			logExecutionRequest(t, testCase.name, testCase.request)
+			value, err := ExecutePlaceholder(testCase.request)
+			logPlaceholderExecutionResult(t, testCase.name, value, err)
+
+			if testCase.expectedError == "" {
+				if err != nil || value != testCase.expectedValue {
+					t.Fatalf("expected %q, got %q (%v)", testCase.expectedValue, value, err)
+				}
+				return
+			}
+			if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInvalidInput || strings.Contains(err.Error(), testCase.expectedError) == false {
+				t.Fatalf("expected invalid_input error containing %q, got: %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLuaPlaceholderMetadata_ShouldValidateDirectLuaCalls(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(nil, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	// Shadow mode calls the Lua function without the dispatcher, the metadata is still checked
+	input, err := newGoPlaceholderInput(PlaceholderExecutionRequest{FunctionName: "HappyLuaTime", ArrayIndexes: []int{1}})
+	if err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+	value, err := executeLuaPlaceholderFunction(input)
+	logPlaceholderExecutionResult(t, "direct-lua-call", value.Format(), err)
+	if PlaceholderErrorKindOf(err) != PlaceholderErrorKindInvalidInput || strings.Contains(err.Error(), "does not support array indexes") == false {
+		t.Fatalf("expected invalid_input error for direct Lua call, got: %v", err)
+	}
+}
+
+func TestLuaPlaceholderMetadata_ShouldReportInvalidMetadataAsLoadError(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "badMetadata", LuaScript: []byte(`
+Test_BadMetadata_meta = { args = { { name = "Value", type = "date" } } }
+function Test_BadMetadata(inputTable) end
+`)}}, DefaultLuaSandboxOptions())
+	t.Logf("Output [invalid-metadata]\n  Error: %v", err)
+
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false || loadError.ScriptName != "badMetadata" || loadError.Phase != LuaScriptLoadPhaseMetadata {
+		t.Fatalf("expected metadata load error for badMetadata, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "Lua script 'badMetadata' has invalid metadata: Test_BadMetadata_meta: argument 'Value' has unknown type 'date'") == false {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package scriptEngine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// sandboxTestLuaScript probes what a domain script can reach and uses up resources on request.
+const sandboxTestLuaScript = `
+local function requireResultOf(moduleName)
+    if pcall(require, moduleName) then
+        return "loaded"
+    end
+    return "blocked"
+end
+
+function Test_SandboxProbe(inputTable)
+    local requireResult = "blocked"
+    if pcall(require, "some_module_on_disk") then
+        requireResult = "loaded"
+    end
+    return { success = true, value = {
+        io = type(io),
+        debug = type(debug),
+        dofile = type(dofile),
+        loadfile = type(loadfile),
+        os_execute = type(os.execute),
+        os_remove = type(os.remove),
+        os_time = type(os.time),
+        os_date = type(os.date),
+        string_format = type(string.format),
+        require_file = requireResult,
+        require_date = type(require("date")),
+        require_os_execute = type(require("os").execute),
+        require_os_remove = type(require("os").remove),
+        require_os_time = type(require("os").time),
+        require_io = requireResultOf("io"),
+        require_debug = requireResultOf("debug"),
+        loaded_os_execute = type(package.loaded.os.execute),
+        loaded_os_is_global = tostring(package.loaded.os == os),
+        loaded_io = type(package.loaded.io),
+        loaded_debug = type(package.loaded.debug),
+        loaded_channel = type(package.loaded.channel),
+    }, errorMessage = "" }
+end
+
+function Test_SandboxLoop(inputTable)
+    while true do end
+end
+
+local function recurse(depth)
+    return recurse(depth + 1) + 1
+end
+
+function Test_SandboxRecursion(inputTable)
+    return { success = true, value = tostring(recurse(1)), errorMessage = "" }
+end
+
+function Test_SandboxUnpack(inputTable)
+    local values = {}
+    for i = 1, 20000 do values[i] = i end
+    return { success = true, value = tostring(select("#", unpack(values))), errorMessage = "" }
+end
+`
+
+func TestLuaSandbox_ShouldOnlyExposeAllowedLibraries(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "sandboxTest", LuaScript: []byte(sandboxTestLuaScript)}},
+		DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	request := PlaceholderExecutionRequest{FunctionName: "Test_SandboxProbe"}
+	logExecutionRequest(t, "sandbox-probe", request)
+	probe, err := ExecutePlaceholderValue(request)
+	logPlaceholderExecutionResult(t, "sandbox-probe", probe.Format(), err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expectedTypes := map[string]string{
+		"io":            "nil",
+		"debug":         "nil",
+		"dofile":        "nil",
+		"loadfile":      "nil",
+		"os_execute":    "nil",
+		"os_remove":     "nil",
+		"os_time":       "function",
+		"os_date":       "function",
+		"string_format": "function",
+		"require_file":  "blocked",
+		"require_date":  "table",
+		// 'require' and 'package.loaded' give the same reduced libraries as the globals
+		"require_os_execute":  "nil",
+		"require_os_remove":   "nil",
+		"require_os_time":     "function",
+		"require_io":          "blocked",
+		"require_debug":       "blocked",
+		"loaded_os_execute":   "nil",
+		"loaded_os_is_global": "true",
+		"loaded_io":           "nil",
+		"loaded_debug":        "nil",
+		"loaded_channel":      "nil",
+	}
+	for field, expectedType := range expectedTypes {
+		value, err := probe.Field(field)
+		if err != nil || value.Format() != expectedType {
+			t.Fatalf("expected %s to be %q, got %q (%v)", field, expectedType, value.Format(), err)
+		}
+	}
+
+	// Fenix Lua functions only need the allowed libraries
+	luaOnlyInput, err := newGoPlaceholderInput(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"0"}})
+	if err != nil {
+		t.Fatalf("failed to build input: %v", err)
+	}
+	value, err := executeLuaPlaceholderFunction(luaOnlyInput)
+	logPlaceholderExecutionResult(t, "sandbox-fenix-lua", value.Format(), err)
+	if err != nil {
+		t.Fatalf("expected Fenix Lua function to run in the sandbox, got: %v", err)
+	}
+}
+
+func TestLuaSandbox_ShouldEnforceResourceLimits(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.CallStackSize = 100
+	luaEngineOptions.RegistryMaxSize = 8192
+	luaEngineOptions.InstructionBudget = 100000
+	if err := InitiateLuaScriptEngineWithOptions(
+		[]LuaScriptsStruct{{LuaScriptName: "sandboxTest", LuaScript: []byte(sandboxTestLuaScript)}},
+		luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	testCases := []struct {
+		functionName  string
+		expectedKind  PlaceholderErrorKind
+		expectedError string
+	}{
+		{functionName: "Test_SandboxLoop", expectedKind: PlaceholderErrorKindResourceLimit, expectedError: "instruction budget of 100000 exceeded"},
+		{functionName: "Test_SandboxRecursion", expectedKind: PlaceholderErrorKindLuaRuntime, expectedError: "stack overflow"},
+		{functionName: "Test_SandboxUnpack", expectedKind: PlaceholderErrorKindLuaRuntime, expectedError: "registry overflow"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.functionName, func(t *testing.T) {
+			request := PlaceholderExecutionRequest{FunctionName: testCase.functionName}
+			logExecutionRequest(t, testCase.functionName, request)
+			value, err := ExecutePlaceholder(request)
+			logPlaceholderExecutionResult(t, testCase.functionName, value, err)
+			if PlaceholderErrorKindOf(err) != testCase.expectedKind || strings.Contains(err.Error(), testCase.expectedError) == false {
+				t.Fatalf("expected %s error containing %q, got: %v", testCase.expectedKind, testCase.expectedError, err)
+			}
+		})
+	}
+
+	// The engine is still usable after a limit was hit
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_SandboxProbe", FieldPath: []string{"os_time"}})
+	if err != nil || value != "function" {
+		t.Fatalf("expected engine to recover after limits, got %q (%v)", value, err)
+	}
+}
+
+func TestInitiateLuaScriptEngineWithOptions_ShouldRejectUnsafeLibraries(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.AllowedLibraries = append(luaEngineOptions.AllowedLibraries, "io")
+
+	err := InitiateLuaScriptEngineWithOptions(nil, luaEngineOptions)
+	t.Logf("Output [unsafe-library]\n  Error: %v", err)
+	if err == nil || strings.Contains(err.Error(), "library 'io' can't be opened in the Lua sandbox") == false {
+		t.Fatalf("expected error for 'io' in the allowlist, got: %v", err)
+	}
+}
+
+func TestInitiateLuaScriptEngineWithOptions_ShouldRequirePackageInMinimalAllowlist(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions(nil, LuaEngineOptions{Sandbox: true, AllowedLibraries: []string{"base", "string"}})
+	t.Logf("Output [without-package]\n  Error: %v", err)
+	if err == nil || strings.Contains(err.Error(), "library 'package' must be allowed in the Lua sandbox") == false {
+		t.Fatalf("expected error for allowlist without 'package', got: %v", err)
+	}
+
+	// The libraries used by the Fenix scripts and 'package' are enough to start the engine
+	if err = InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}},
+		LuaEngineOptions{Sandbox: true, AllowedLibraries: []string{"base", "package", "string", "math", "os"}}); err != nil {
+		t.Fatalf("failed to initiate Lua engine with minimal allowlist: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+	if value := executeScriptVersion(t, "minimal-allowlist"); value != "v1" {
+		t.Fatalf("expected v1, got %q", value)
+	}
+}
+
+func TestInitiateLuaScriptEngineWithOptions_ShouldApplyInstructionBudgetWhileLoading(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.InstructionBudget = 100000
+
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "endless", LuaScript: []byte("local a = 1\nwhile true do end\n")}}, luaEngineOptions)
+	logLuaScriptLoadErrors(t, "endless-script", err)
+
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false || loadError.ScriptName != "endless" || loadError.Phase != LuaScriptLoadPhaseRun ||
+		strings.Contains(loadError.Message, "instruction budget of 100000 exceeded") == false {
+		t.Fatalf("expected instruction budget run error, got: %v", err)
+	}
+}
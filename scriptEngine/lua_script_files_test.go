@@ -0,0 +1,173 @@
+package scriptEngine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// scriptVersionLuaScript returns a version text, so a test can see which script version is loaded.
+func scriptVersionLuaScript(version string) string {
+	return `
+function Test_ScriptVersion(inputTable)
+    return { success = true, value = "` + version + `", errorMessage = "" }
+end
+
+function Test_ScriptSlow(inputTable)
+    local sum = 0
+    for i = 1, 3000000 do sum = sum + i end
+    return { success = true, value = "` + version + `", errorMessage = "" }
+end
+`
+}
+
+func executeScriptVersion(t *testing.T, callLabel string) string {
+	t.Helper()
+
+	value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ScriptVersion"})
+	logPlaceholderExecutionResult(t, callLabel, value, err)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	return value
+}
+
+func TestLoadLuaScriptsFromFS_ShouldLoadAllLuaFilesSortedByPath(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"scripts/b.lua":          {Data: []byte("-- b")},
+		"scripts/a.lua":          {Data: []byte("-- a")},
+		"scripts/customer/c.lua": {Data: []byte("-- c")},
+		"scripts/README.md":      {Data: []byte("not a script")},
+		"other/d.lua":            {Data: []byte("-- d")},
+	}
+
+	luaScriptFiles, err := LoadLuaScriptsFromFS(fileSystem, "scripts")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var scriptNames []string
+	for _, luaScriptFile := range luaScriptFiles {
+		scriptNames = append(scriptNames, luaScriptFile.LuaScriptName)
+	}
+	t.Logf("Output [load-from-fs]\n  ScriptNames: %v", scriptNames)
+	if strings.Join(scriptNames, ",") != "a,b,customer/c" || string(luaScriptFiles[2].LuaScript) != "-- c" {
+		t.Fatalf("unexpected scripts: %v", scriptNames)
+	}
+
+	if _, err = LoadLuaScriptsFromFS(fileSystem, "missing"); err == nil {
+		t.Fatalf("expected error for missing directory")
+	}
+}
+
+func TestReloadLuaScripts_ShouldSwapValidScriptsAndKeepRunningEngineOnError(t *testing.T) {
+	CloseDownLuaScriptEngine()
+	if err := ReloadLuaScripts(nil); err == nil {
+		t.Fatalf("expected error without initiated engine")
+	}
+
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = 2
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	// A running call finishes on the previous scripts while the reload waits for it
+	slowDone := make(chan string)
+	go func() {
+		value, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_ScriptSlow"})
+		if err != nil {
+			value = err.Error()
+		}
+		slowDone <- value
+	}()
+	for GetLuaStatePoolStats().InUse == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := ReloadLuaScripts([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v2"))}}); err != nil {
+		t.Fatalf("expected reload without error, got: %v", err)
+	}
+	if slowValue := <-slowDone; slowValue != "v1" {
+		t.Fatalf("expected in-flight call to finish on v1, got %q", slowValue)
+	}
+	if value := executeScriptVersion(t, "after-reload"); value != "v2" {
+		t.Fatalf("expected v2 after reload, got %q", value)
+	}
+	if stats := GetLuaStatePoolStats(); stats.Size != 2 {
+		t.Fatalf("expected reload to keep the pool size, got %+v", stats)
+	}
+
+	err := ReloadLuaScripts([]LuaScriptsStruct{{LuaScriptName: "broken", LuaScript: []byte("function Test_ScriptVersion(")}})
+	t.Logf("Output [broken-reload]\n  Error: %v", err)
+	if err == nil || strings.Contains(err.Error(), "Lua script 'broken' failed to compile") == false {
+		t.Fatalf("expected load error for broken script, got: %v", err)
+	}
+	if value := executeScriptVersion(t, "after-broken-reload"); value != "v2" {
+		t.Fatalf("expected v2 to stay after a failed reload, got %q", value)
+	}
+}
+
+func TestWatchLuaScripts_ShouldReloadChangedFiles(t *testing.T) {
+	scriptDirectory := t.TempDir()
+	scriptPath := filepath.Join(scriptDirectory, "version.lua")
+	if err := os.WriteFile(scriptPath, []byte(scriptVersionLuaScript("v1")), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	luaScriptFiles, err := LoadLuaScriptsFromDirectory(scriptDirectory)
+	if err != nil {
+		t.Fatalf("failed to load scripts: %v", err)
+	}
+	if err = InitiateLuaScriptEngineWithOptions(luaScriptFiles, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	reloadResults := make(chan error, 10)
+	watcher, err := WatchLuaScripts(os.DirFS(scriptDirectory), ".", LuaScriptWatchOptions{
+		PollInterval: 10 * time.Millisecond,
+		OnReload:     func(err error) { reloadResults <- err },
+	})
+	if err != nil {
+		t.Fatalf("failed to watch scripts: %v", err)
+	}
+	defer watcher.Stop()
+
+	// A poll can see a file while it is written, so results are read until the expected one arrives
+	waitForReload := func(callLabel string, expectError bool) {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case err := <-reloadResults:
+				t.Logf("Output [%s]\n  ReloadError: %v", callLabel, err)
+				if (err != nil) == expectError {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("no reload with expected result for %s", callLabel)
+			}
+		}
+	}
+
+	if err = os.WriteFile(scriptPath, []byte(scriptVersionLuaScript("v2")), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	waitForReload("changed-file", false)
+	if value := executeScriptVersion(t, "watched-v2"); value != "v2" {
+		t.Fatalf("expected v2 after file change, got %q", value)
+	}
+
+	if err = os.WriteFile(scriptPath, []byte("function Test_ScriptVersion("), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	waitForReload("broken-file", true)
+	if value := executeScriptVersion(t, "watched-after-broken"); value != "v2" {
+		t.Fatalf("expected v2 to stay after a broken file, got %q", value)
+	}
+}
@@ -0,0 +1,229 @@
+package scriptEngine
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// integrityFailingLuaScript fails when it runs, so a test can see that a refused set of scripts was never run.
+const integrityFailingLuaScript = `error("this script must not run")`
+
+// integrityTestPrivateKey returns a fixed Ed25519 key, derived from a seed byte.
+func integrityTestPrivateKey(seedByte byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed([]byte(strings.Repeat(string(rune(seedByte)), ed25519.SeedSize)))
+}
+
+func logLuaScriptLoadReport(t *testing.T, callLabel string, report []LuaScriptLoadReport) {
+	t.Helper()
+	t.Logf("Output [%s]\n  Scripts: %d", callLabel, len(report))
+	for _, scriptLoadReport := range report {
+		t.Logf("  %s %s %s %s", scriptLoadReport.ScriptName, scriptLoadReport.SHA256, scriptLoadReport.Verification, scriptLoadReport.SignedBy)
+	}
+}
+
+func TestLuaScriptIntegrity_ShouldLoadScriptsInManifestOrSignedByTrustedKey(t *testing.T) {
+	signingKey := integrityTestPrivateKey(1)
+	signedScript := []byte(scriptVersionLuaScript("signed"))
+
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.Integrity = &LuaScriptIntegrityOptions{
+		AllowedSHA256:     map[string]string{"version": strings.ToUpper(luaScriptSHA256([]byte(loadErrorsValidLuaScript)))},
+		TrustedPublicKeys: []ed25519.PublicKey{integrityTestPrivateKey(2).Public().(ed25519.PublicKey), signingKey.Public().(ed25519.PublicKey)},
+	}
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "version", LuaScript: []byte(loadErrorsValidLuaScript)},
+		{LuaScriptName: "signed", LuaScript: signedScript, Signature: ed25519.Sign(signingKey, signedScript)},
+	}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	if value := executeScriptVersion(t, "signed-script"); value != "signed" {
+		t.Fatalf("expected signed script to be loaded, got %q", value)
+	}
+
+	report := GetLuaScriptLoadReport()
+	logLuaScriptLoadReport(t, "integrity-report", report)
+	if report[0].Verification != LuaScriptVerificationEmbedded || report[0].SHA256 != luaScriptSHA256(fenix_ControlledUniqueId) {
+		t.Fatalf("expected Fenix scripts to be reported as embedded, got %+v", report[0])
+	}
+	domainReport := report[len(report)-2:]
+	if domainReport[0].Verification != LuaScriptVerificationManifest || domainReport[0].SHA256 != luaScriptSHA256([]byte(loadErrorsValidLuaScript)) {
+		t.Fatalf("expected manifest verification, got %+v", domainReport[0])
+	}
+	signingPublicKey := hex.EncodeToString(signingKey.Public().(ed25519.PublicKey))
+	if domainReport[1].Verification != LuaScriptVerificationSignature || domainReport[1].SignedBy != signingPublicKey {
+		t.Fatalf("expected signature verification by the signing key, got %+v", domainReport[1])
+	}
+
+	// The report is written as JSON for compliance audits
+	reportJSON, err := json.Marshal(domainReport[1])
+	if err != nil || strings.Contains(string(reportJSON), `"verification":"signature","signedBy":"`+signingPublicKey+`"`) == false {
+		t.Fatalf("unexpected report JSON: %s (%v)", reportJSON, err)
+	}
+}
+
+func TestLuaScriptIntegrity_ShouldRefuseScriptsThatDontVerifyWithoutRunningAnyScript(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	signingKey := integrityTestPrivateKey(1)
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.Integrity = &LuaScriptIntegrityOptions{
+		AllowedSHA256:     map[string]string{"changed": luaScriptSHA256([]byte("-- reviewed version"))},
+		TrustedPublicKeys: []ed25519.PublicKey{signingKey.Public().(ed25519.PublicKey)},
+	}
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "changed", LuaScript: []byte(integrityFailingLuaScript)},
+		{LuaScriptName: "unsigned", LuaScript: []byte(integrityFailingLuaScript)},
+		{LuaScriptName: "tampered", LuaScript: []byte(integrityFailingLuaScript), Signature: ed25519.Sign(signingKey, []byte("-- signed version"))},
+	}, luaEngineOptions)
+	logLuaScriptLoadErrors(t, "refused-scripts", err)
+
+	// Only integrity errors: the failing top level of the scripts never ran
+	var loadErrors LuaScriptLoadErrors
+	if errors.As(err, &loadErrors) == false || len(loadErrors) != 3 {
+		t.Fatalf("expected three integrity errors, got: %v", err)
+	}
+	failingSHA256 := luaScriptSHA256([]byte(integrityFailingLuaScript))
+	expectedMessages := []string{
+		"SHA-256 " + failingSHA256 + " does not match the manifest hash " + luaScriptSHA256([]byte("-- reviewed version")) + " and the script is not signed",
+		"SHA-256 " + failingSHA256 + " is not in the manifest and the script is not signed",
+		"SHA-256 " + failingSHA256 + " is not in the manifest and the signature is not valid for any trusted public key",
+	}
+	for errorIndex, expectedMessage := range expectedMessages {
+		if loadErrors[errorIndex].Phase != LuaScriptLoadPhaseIntegrity || loadErrors[errorIndex].Message != expectedMessage {
+			t.Fatalf("expected %q, got: %+v", expectedMessage, loadErrors[errorIndex])
+		}
+	}
+	if strings.Contains(err.Error(), "Lua script 'unsigned' failed integrity verification: SHA-256") == false {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+
+	// The running engine is kept
+	if value := executeScriptVersion(t, "after-refused-scripts"); value != "v1" {
+		t.Fatalf("expected running engine to stay, got %q", value)
+	}
+}
+
+func TestLuaScriptIntegrity_ShouldVerifyReloadedScripts(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.Integrity = &LuaScriptIntegrityOptions{
+		AllowedSHA256: map[string]string{"version": luaScriptSHA256([]byte(scriptVersionLuaScript("v1")))},
+	}
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	err := ReloadLuaScripts([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v2"))}})
+	logLuaScriptLoadErrors(t, "reload-changed-script", err)
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false || loadError.Phase != LuaScriptLoadPhaseIntegrity {
+		t.Fatalf("expected integrity error for changed script, got: %v", err)
+	}
+	if value := executeScriptVersion(t, "after-refused-reload"); value != "v1" {
+		t.Fatalf("expected running scripts to stay, got %q", value)
+	}
+}
+
+func TestLuaScriptIntegrity_ShouldRejectInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		integrity     LuaScriptIntegrityOptions
+		expectedError string
+	}{
+		{
+			name:          "short hash",
+			integrity:     LuaScriptIntegrityOptions{AllowedSHA256: map[string]string{"version": "abc"}},
+			expectedError: "manifest hash of Lua script 'version' must be 32 hex encoded bytes, got 'abc'",
+		},
+		{
+			name:          "short public key",
+			integrity:     LuaScriptIntegrityOptions{TrustedPublicKeys: []ed25519.PublicKey{ed25519.PublicKey("short")}},
+			expectedError: "trusted public key 1 must be 32 bytes, got 5",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			luaEngineOptions := DefaultLuaSandboxOptions()
+			luaEngineOptions.Integrity = &testCase.integrity
+			err := InitiateLuaScriptEngineWithOptions(nil, luaEngineOptions)
+			logLuaScriptLoadErrors(t, testCase.name, err)
+			if err == nil || err.Error() != testCase.expectedError {
+				t.Fatalf("expected %q, got: %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestParseLuaScriptHashManifest_ShouldReadSha256sumOutput(t *testing.T) {
+	customerSHA256 := luaScriptSHA256([]byte("-- customer"))
+	formatSHA256 := luaScriptSHA256([]byte("-- format"))
+	manifest := "# reviewed scripts\n\n" +
+		customerSHA256 + "  ./customer/Address.lua\n" +
+		strings.ToUpper(formatSHA256) + " *modules/shared/format.lua\n"
+
+	allowedSHA256, err := ParseLuaScriptHashManifest(strings.NewReader(manifest))
+	t.Logf("Output [manifest]\n  AllowedSHA256: %v\n  Error: %v", allowedSHA256, err)
+	if err != nil || len(allowedSHA256) != 2 ||
+		allowedSHA256["customer/Address"] != customerSHA256 || allowedSHA256["modules/shared/format"] != formatSHA256 {
+		t.Fatalf("unexpected manifest: %v (%v)", allowedSHA256, err)
+	}
+
+	invalidManifests := map[string]string{
+		"no file":     customerSHA256 + "\n",
+		"short hash":  "abc  customer.lua\n",
+		"second hash": customerSHA256 + "  customer.lua\n" + formatSHA256 + "  customer.lua\n",
+	}
+	for name, invalidManifest := range invalidManifests {
+		if _, err = ParseLuaScriptHashManifest(strings.NewReader(invalidManifest)); err == nil {
+			t.Fatalf("expected error for manifest with %s", name)
+		}
+	}
+}
+
+func TestLoadLuaScriptsFromFS_ShouldReadSignatureFiles(t *testing.T) {
+	signingKey := integrityTestPrivateKey(1)
+	rawSignedScript := []byte(scriptVersionLuaScript("raw"))
+	base64SignedScript := []byte("-- base64")
+	fileSystem := fstest.MapFS{
+		"scripts/raw.lua":        {Data: rawSignedScript},
+		"scripts/raw.lua.sig":    {Data: ed25519.Sign(signingKey, rawSignedScript)},
+		"scripts/base64.lua":     {Data: base64SignedScript},
+		"scripts/base64.lua.sig": {Data: []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(signingKey, base64SignedScript)) + "\n")},
+		"scripts/unsigned.lua":   {Data: []byte("-- unsigned")},
+	}
+
+	luaScriptFiles, err := LoadLuaScriptsFromFS(fileSystem, "scripts")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Logf("Output [signature-files]\n  Scripts: %d", len(luaScriptFiles))
+	if len(luaScriptFiles) != 3 || len(luaScriptFiles[0].Signature) != ed25519.SignatureSize ||
+		len(luaScriptFiles[1].Signature) != ed25519.SignatureSize || luaScriptFiles[2].Signature != nil {
+		t.Fatalf("unexpected scripts: %+v", luaScriptFiles)
+	}
+
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.Integrity = &LuaScriptIntegrityOptions{TrustedPublicKeys: []ed25519.PublicKey{signingKey.Public().(ed25519.PublicKey)}}
+	if err = InitiateLuaScriptEngineWithOptions(luaScriptFiles[:2], luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	fileSystem["scripts/raw.lua.sig"] = &fstest.MapFile{Data: []byte("not a signature")}
+	if _, err = LoadLuaScriptsFromFS(fileSystem, "scripts"); err == nil || strings.Contains(err.Error(), "invalid signature file") == false {
+		t.Fatalf("expected invalid signature file error, got: %v", err)
+	}
+}
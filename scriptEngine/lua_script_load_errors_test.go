@@ -0,0 +1,119 @@
+package scriptEngine
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadErrorsValidLuaScript defines one global function and one local function.
+const loadErrorsValidLuaScript = `
+function Test_LoadErrorsValid(inputTable)
+    return { success = true, value = "valid", errorMessage = "" }
+end
+
+local function helper()
+end
+`
+
+func logLuaScriptLoadErrors(t *testing.T, callLabel string, err error) {
+	t.Helper()
+	t.Logf("Output [%s]\n  Error: %v", callLabel, err)
+}
+
+func TestInitiateLuaScriptEngine_ShouldReportSyntaxErrorWithScriptNameAndLine(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "customer/Address", LuaScript: []byte("local a = 1\n\nlocal b = = 2\n")},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "syntax-error", err)
+
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false {
+		t.Fatalf("expected LuaScriptLoadError, got: %v", err)
+	}
+	if loadError.ScriptName != "customer/Address" || loadError.Phase != LuaScriptLoadPhaseCompile || loadError.Line != 3 {
+		t.Fatalf("unexpected load error: %+v", loadError)
+	}
+	if strings.Contains(err.Error(), "Lua script 'customer/Address' failed to compile at line 3") == false {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
+
+func TestInitiateLuaScriptEngine_ShouldReportRuntimeErrorWithScriptNameAndLine(t *testing.T) {
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "runtimeFailure", LuaScript: []byte("local a = 1\nlocal b = nil\nlocal c = b.field\n")},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "runtime-error", err)
+
+	var loadError *LuaScriptLoadError
+	if errors.As(err, &loadError) == false {
+		t.Fatalf("expected LuaScriptLoadError, got: %v", err)
+	}
+	if loadError.ScriptName != "runtimeFailure" || loadError.Phase != LuaScriptLoadPhaseRun || loadError.Line != 3 {
+		t.Fatalf("unexpected load error: %+v", loadError)
+	}
+	if strings.HasPrefix(loadError.Message, "runtimeFailure:") == true {
+		t.Fatalf("expected script name and line to be removed from message, got %q", loadError.Message)
+	}
+}
+
+func TestInitiateLuaScriptEngine_ShouldAggregateAllLoadErrorsAndNotStartEngine(t *testing.T) {
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "version", LuaScript: []byte(scriptVersionLuaScript("v1"))}}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "first", LuaScript: []byte("function (")},
+		{LuaScriptName: "valid", LuaScript: []byte(loadErrorsValidLuaScript)},
+		{LuaScriptName: "second", LuaScript: []byte("error('configuration missing')")},
+	}, DefaultLuaSandboxOptions())
+	logLuaScriptLoadErrors(t, "aggregated-errors", err)
+
+	var loadErrors LuaScriptLoadErrors
+	if errors.As(err, &loadErrors) == false || len(loadErrors) != 2 {
+		t.Fatalf("expected two aggregated load errors, got: %v", err)
+	}
+	if loadErrors[0].ScriptName != "first" || loadErrors[1].ScriptName != "second" || loadErrors[1].Message != "configuration missing" {
+		t.Fatalf("unexpected load errors: %+v, %+v", loadErrors[0], loadErrors[1])
+	}
+	if strings.HasPrefix(err.Error(), "2 Lua script(s) failed to load") == false {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+
+	// The running engine is kept when the new scripts fail
+	if value := executeScriptVersion(t, "after-failed-initiate"); value != "v1" {
+		t.Fatalf("expected running engine to stay, got %q", value)
+	}
+}
+
+func TestGetLuaScriptLoadReport_ShouldListDefinedFunctionsPerScript(t *testing.T) {
+	CloseDownLuaScriptEngine()
+	if report := GetLuaScriptLoadReport(); report != nil {
+		t.Fatalf("expected no report without engine, got %+v", report)
+	}
+
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{
+		{LuaScriptName: "valid", LuaScript: []byte(loadErrorsValidLuaScript)},
+		{LuaScriptName: "override", LuaScript: []byte(loadErrorsValidLuaScript)},
+		{LuaScriptName: "empty", LuaScript: []byte("-- nothing")},
+	}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	report := GetLuaScriptLoadReport()
+	domainReport := report[len(report)-3:]
+	t.Logf("Output [load-report]\n  Report: %+v", domainReport)
+
+	validSHA256 := luaScriptSHA256([]byte(loadErrorsValidLuaScript))
+	expectedReport := []LuaScriptLoadReport{
+		{ScriptName: "valid", SHA256: validSHA256, Verification: LuaScriptVerificationNone, DefinedFunctions: []string{"Test_LoadErrorsValid"}},
+		{ScriptName: "override", SHA256: validSHA256, Verification: LuaScriptVerificationNone, DefinedFunctions: []string{"Test_LoadErrorsValid"}},
+		{ScriptName: "empty", SHA256: luaScriptSHA256([]byte("-- nothing")), Verification: LuaScriptVerificationNone},
+	}
+	if reflect.DeepEqual(domainReport, expectedReport) == false {
+		t.Fatalf("unexpected load report: %+v", domainReport)
+	}
+}
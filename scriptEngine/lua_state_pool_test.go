@@ -0,0 +1,116 @@
+package scriptEngine
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// poolTestLuaScript echoes the first argument and uses the preloaded 'date' module.
+const poolTestLuaScript = `
+local date = require("date")
+
+function Test_PoolEcho(inputTable)
+    local functionArgs = inputTable[3]
+    return { success = true, value = functionArgs[1] .. "-" .. date(2024, 1, 31):fmt("%Y-%m-%d"), errorMessage = "" }
+end
+`
+
+func logLuaStatePoolStats(t *testing.T, callLabel string, stats LuaStatePoolStats) {
+	t.Helper()
+	t.Logf(
+		"Lua state pool [%s]\n  Size: %d\n  InUse: %d\n  CheckOuts: %d\n  Waits: %d\n  WaitTime: %s",
+		callLabel,
+		stats.Size,
+		stats.InUse,
+		stats.CheckOuts,
+		stats.Waits,
+		stats.WaitTime,
+	)
+}
+
+func TestLuaStatePool_ShouldExecuteConcurrentCallsOnAllStates(t *testing.T) {
+	luaEngineOptions := DefaultLuaSandboxOptions()
+	luaEngineOptions.PoolSize = 4
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "poolTest", LuaScript: []byte(poolTestLuaScript)}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	const numberOfCalls = 32
+	values := make([]string, numberOfCalls)
+	errs := make([]error, numberOfCalls)
+	var waitGroup sync.WaitGroup
+	for callIndex := 0; callIndex < numberOfCalls; callIndex++ {
+		waitGroup.Add(1)
+		go func(callIndex int) {
+			defer waitGroup.Done()
+			values[callIndex], errs[callIndex] = ExecutePlaceholder(PlaceholderExecutionRequest{
+				FunctionName: "Test_PoolEcho",
+				Arguments:    []string{fmt.Sprint(callIndex)},
+			})
+		}(callIndex)
+	}
+	waitGroup.Wait()
+
+	for callIndex := 0; callIndex < numberOfCalls; callIndex++ {
+		expectedValue := fmt.Sprintf("%d-2024-01-31", callIndex)
+		if errs[callIndex] != nil || values[callIndex] != expectedValue {
+			t.Fatalf("expected %q, got %q (%v)", expectedValue, values[callIndex], errs[callIndex])
+		}
+	}
+
+	stats := GetLuaStatePoolStats()
+	logLuaStatePoolStats(t, "concurrent-calls", stats)
+	if stats.Size != 4 || stats.InUse != 0 || stats.CheckOuts != numberOfCalls {
+		t.Fatalf("unexpected pool stats: %+v", stats)
+	}
+}
+
+func TestLuaStatePool_ShouldWaitForFreeState(t *testing.T) {
+	luaEngineOptions := LuaEngineOptions{PoolSize: 2}
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "poolTest", LuaScript: []byte(poolTestLuaScript)}}, luaEngineOptions); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	firstState := luaEngine.checkOut()
+	secondState := luaEngine.checkOut()
+	if stats := GetLuaStatePoolStats(); stats.InUse != 2 {
+		t.Fatalf("expected 2 states in use, got %+v", stats)
+	}
+
+	done := make(chan string)
+	go func() {
+		value, _ := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_PoolEcho", Arguments: []string{"waiting"}})
+		done <- value
+	}()
+
+	select {
+	case value := <-done:
+		t.Fatalf("expected call to wait for a free state, got %q", value)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	luaEngine.checkIn(firstState)
+	value := <-done
+	luaEngine.checkIn(secondState)
+
+	stats := GetLuaStatePoolStats()
+	logLuaStatePoolStats(t, "wait-for-state", stats)
+	if value != "waiting-2024-01-31" || stats.Waits != 1 || stats.WaitTime <= 0 || stats.InUse != 0 {
+		t.Fatalf("expected one waiting call, got %q and %+v", value, stats)
+	}
+}
+
+func TestLuaStatePool_ShouldReportZeroStatsAndRejectNegativeSize(t *testing.T) {
+	CloseDownLuaScriptEngine()
+
+	if stats := GetLuaStatePoolStats(); stats != (LuaStatePoolStats{}) {
+		t.Fatalf("expected zero stats without engine, got %+v", stats)
+	}
+	if err := InitiateLuaScriptEngineWithOptions(nil, LuaEngineOptions{PoolSize: -1}); err == nil {
+		t.Fatalf("expected error for negative pool size")
+	}
+}
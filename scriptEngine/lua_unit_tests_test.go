@@ -0,0 +1,194 @@
+package scriptEngine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// luaUnitTestsDirectory holds the Lua modules under 'src' and the Lua unit tests under 'tests'.
+const luaUnitTestsDirectory = "../luaEngine"
+
+// TestLuaUnitTests runs every 'tests/test_*.lua' file in luaEngine. A test file returns a table of test functions,
+// and every function is run as one subtest named after the file and the function.
+func TestLuaUnitTests(t *testing.T) {
+	luaTestFiles, err := filepath.Glob(filepath.Join(luaUnitTestsDirectory, "tests", "test_*.lua"))
+	if err != nil {
+		t.Fatalf("failed to find Lua test files: %v", err)
+	}
+	if len(luaTestFiles) == 0 {
+		t.Fatalf("expected Lua test files in %s", filepath.Join(luaUnitTestsDirectory, "tests"))
+	}
+	sort.Strings(luaTestFiles)
+
+	for _, luaTestFile := range luaTestFiles {
+		luaTestFile := luaTestFile
+		t.Run(strings.TrimSuffix(filepath.Base(luaTestFile), ".lua"), func(t *testing.T) {
+			runLuaUnitTestFile(t, luaTestFile)
+		})
+	}
+}
+
+// runLuaUnitTestFile loads one test file in a new Lua state and runs its test functions in name order.
+func runLuaUnitTestFile(t *testing.T, luaTestFile string) {
+	luaState := newLuaUnitTestState(t)
+	defer luaState.Close()
+
+	luaTestScript, err := os.ReadFile(luaTestFile)
+	if err != nil {
+		t.Fatalf("failed to read Lua test file: %v", err)
+	}
+
+	// The chunk is named like the path luatest uses, so failures point at 'tests/<file>.lua:<line>'
+	chunkName := filepath.ToSlash(filepath.Join("tests", filepath.Base(luaTestFile)))
+	testFileFunction, err := luaState.Load(bytes.NewReader(luaTestScript), chunkName)
+	if err != nil {
+		t.Fatalf("failed to compile Lua test file: %v", err)
+	}
+	luaState.Push(testFileFunction)
+	if err = luaState.PCall(0, 1, nil); err != nil {
+		t.Fatalf("failed to load Lua test file: %v", err)
+	}
+	testsTable, ok := luaState.Get(-1).(*lua.LTable)
+	luaState.Pop(1)
+	if ok == false {
+		t.Fatalf("expected Lua test file %s to return a table of test functions", chunkName)
+	}
+
+	testFunctions := map[string]*lua.LFunction{}
+	testsTable.ForEach(func(key lua.LValue, value lua.LValue) {
+		if testFunction, ok := value.(*lua.LFunction); ok == true {
+			testFunctions[key.String()] = testFunction
+		}
+	})
+	testNames := make([]string, 0, len(testFunctions))
+	for testName := range testFunctions {
+		testNames = append(testNames, testName)
+	}
+	sort.Strings(testNames)
+
+	for _, testName := range testNames {
+		testFunction := testFunctions[testName]
+		t.Run(testName, func(t *testing.T) {
+			// The clock stands still during a test, so 'os.date' gives the same time in the test and the module
+			restoreTimeProvider := setTestTimeProvider(time.Now())
+			defer restoreTimeProvider()
+
+			err := luaState.CallByParam(lua.P{Fn: testFunction, NRet: 0, Protect: true})
+			t.Logf("Output [%s]\n  File: %s\n  Error: %v", testName, chunkName, err)
+			if err != nil {
+				t.Fatalf("Lua test failed: %v", err)
+			}
+		})
+	}
+}
+
+// newLuaUnitTestState creates a Lua state with the same libraries and preloads as InitiateLuaScriptEngine,
+// a 'luassert' shim and every module in 'src' registered as module 'src/<name>'.
+func newLuaUnitTestState(t *testing.T) *lua.LState {
+	t.Helper()
+
+	luaModuleFiles, err := filepath.Glob(filepath.Join(luaUnitTestsDirectory, "src", "*.lua"))
+	if err != nil {
+		t.Fatalf("failed to find Lua modules: %v", err)
+	}
+	var luaModules []LuaScriptsStruct
+	for _, luaModuleFile := range luaModuleFiles {
+		luaModuleScript, err := os.ReadFile(luaModuleFile)
+		if err != nil {
+			t.Fatalf("failed to read Lua module: %v", err)
+		}
+		// The script name is the file name, so errors in a module show file and line
+		moduleName := "src/" + strings.TrimSuffix(filepath.Base(luaModuleFile), ".lua")
+		luaModules = append(luaModules, LuaScriptsStruct{
+			LuaScriptName: moduleName + ".lua", LuaScript: luaModuleScript, IsModule: true, ModuleName: moduleName})
+	}
+
+	luaState, _, loadErrors := newInitiatedLuaState(luaModules, LuaEngineOptions{}, false)
+	if len(loadErrors) > 0 {
+		t.Fatalf("failed to initiate Lua state: %v", loadErrors)
+	}
+	luaState.PreloadModule("luassert", openLuaAssertShim)
+
+	return luaState
+}
+
+// openLuaAssertShim returns the subset of luassert the Lua unit tests use. A failing assertion raises an error
+// with the file and line of the test that called it.
+func openLuaAssertShim(L *lua.LState) int {
+	isEqual := func(L *lua.LState) int {
+		expected, actual := L.Get(1), L.Get(2)
+		if L.Equal(expected, actual) == false {
+			L.RaiseError("expected %s, got %s", describeLuaAssertValue(expected), describeLuaAssertValue(actual))
+		}
+		return 0
+	}
+	isTrue := func(L *lua.LState) int {
+		if L.Get(1) != lua.LTrue {
+			L.RaiseError("expected true, got %s", describeLuaAssertValue(L.Get(1)))
+		}
+		return 0
+	}
+	isFalse := func(L *lua.LState) int {
+		if L.Get(1) != lua.LFalse {
+			L.RaiseError("expected false, got %s", describeLuaAssertValue(L.Get(1)))
+		}
+		return 0
+	}
+	isNil := func(L *lua.LState) int {
+		if L.Get(1) != lua.LNil {
+			L.RaiseError("expected nil, got %s", describeLuaAssertValue(L.Get(1)))
+		}
+		return 0
+	}
+	isNotNil := func(L *lua.LState) int {
+		if L.Get(1) == lua.LNil {
+			L.RaiseError("expected a value, got nil")
+		}
+		return 0
+	}
+	matches := func(L *lua.LState) int {
+		pattern, actual := L.CheckString(1), L.Get(2)
+		actualString, ok := actual.(lua.LString)
+		if ok == false {
+			L.RaiseError("expected a string matching %q, got %s", pattern, describeLuaAssertValue(actual))
+		}
+		L.Push(L.GetField(L.GetGlobal("string"), "find"))
+		L.Push(actualString)
+		L.Push(lua.LString(pattern))
+		L.Call(2, 1)
+		if L.Get(-1) == lua.LNil {
+			L.RaiseError("expected %s to match %q", describeLuaAssertValue(actual), pattern)
+		}
+		return 0
+	}
+
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"is_equal":   isEqual,
+		"are_equal":  isEqual,
+		"equal":      isEqual,
+		"equals":     isEqual,
+		"is_true":    isTrue,
+		"is_false":   isFalse,
+		"is_nil":     isNil,
+		"is_not_nil": isNotNil,
+		"matches":    matches,
+	}))
+	return 1
+}
+
+// describeLuaAssertValue formats a value for an assertion message, with quotes around strings.
+func describeLuaAssertValue(value lua.LValue) string {
+	if value.Type() == lua.LTString {
+		return fmt.Sprintf("(string) %q", value.String())
+	}
+
+	return fmt.Sprintf("(%s) %s", value.Type(), value.String())
+}
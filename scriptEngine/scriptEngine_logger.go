@@ -0,0 +1,28 @@
+package scriptEngine
+
+import (
+	"log/slog"
+
+	"github.com/jlambert68/FenixScriptEngine/internal/engineLogger"
+)
+
+// Keys of the structured fields in log records.
+const (
+	logKeyFunction      = "function"
+	logKeyExecutionUUID = "executionUuid"
+	logKeyScript        = "script"
+)
+
+// scriptEngineLog holds the logger for engine diagnostics. Nothing is logged until SetLogger is called.
+var scriptEngineLog engineLogger.Logger
+
+// SetLogger sets the logger for diagnostics of the script engine, such as loaded scripts, dispatch decisions,
+// failed placeholder calls and Lua output. Nil makes the engine silent again, which is the default.
+func SetLogger(logger *slog.Logger) {
+	scriptEngineLog.Set(logger)
+}
+
+// scriptEngineLogger returns the logger set with SetLogger, or a logger that discards everything.
+func scriptEngineLogger() *slog.Logger {
+	return scriptEngineLog.Get()
+}
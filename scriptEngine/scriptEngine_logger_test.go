@@ -0,0 +1,117 @@
+package scriptEngine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuffer is a bytes.Buffer that can be written from many goroutines.
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (lockedBuffer *lockedBuffer) Write(p []byte) (int, error) {
+	lockedBuffer.mutex.Lock()
+	defer lockedBuffer.mutex.Unlock()
+
+	return lockedBuffer.buffer.Write(p)
+}
+
+// records decodes the JSON log records written so far.
+func (lockedBuffer *lockedBuffer) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	lockedBuffer.mutex.Lock()
+	defer lockedBuffer.mutex.Unlock()
+
+	var logRecords []map[string]interface{}
+	for _, logLine := range strings.Split(strings.TrimSpace(lockedBuffer.buffer.String()), "\n") {
+		if logLine == "" {
+			continue
+		}
+		logRecord := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(logLine), &logRecord); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", logLine, err)
+		}
+		logRecords = append(logRecords, logRecord)
+	}
+
+	return logRecords
+}
+
+// findLogRecord returns the first record with the message and all the fields.
+func findLogRecord(logRecords []map[string]interface{}, message string, fields map[string]interface{}) map[string]interface{} {
+	for _, logRecord := range logRecords {
+		if logRecord["msg"] != message {
+			continue
+		}
+		matchesFields := true
+		for fieldName, fieldValue := range fields {
+			if logRecord[fieldName] != fieldValue {
+				matchesFields = false
+			}
+		}
+		if matchesFields == true {
+			return logRecord
+		}
+	}
+
+	return nil
+}
+
+func TestSetLogger_ShouldLogEngineDiagnosticsWithStructuredFields(t *testing.T) {
+	logBuffer := &lockedBuffer{}
+	SetLogger(slog.New(slog.NewJSONHandler(logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	if err := InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "loggerTest", LuaScript: []byte(`
+function Test_LoggerPrint(inputTable)
+    print("hello from Lua")
+    return { success = true, value = "ok", errorMessage = "" }
+end
+`)}}, DefaultLuaSandboxOptions()); err != nil {
+		t.Fatalf("failed to initiate Lua engine: %v", err)
+	}
+	defer CloseDownLuaScriptEngine()
+
+	if _, err := ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Test_LoggerPrint", TestCaseExecutionUUID: "logger-execution"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	_, _ = ExecutePlaceholder(PlaceholderExecutionRequest{FunctionName: "Fenix_TodayShiftDay", Arguments: []string{"x"}, TestCaseExecutionUUID: "logger-execution"})
+	_ = InitiateLuaScriptEngineWithOptions([]LuaScriptsStruct{{LuaScriptName: "loggerBroken", LuaScript: []byte("function (")}}, DefaultLuaSandboxOptions())
+
+	logRecords := logBuffer.records(t)
+	t.Logf("Output [engine-log]\n  Records: %d", len(logRecords))
+
+	expectedLogRecords := []struct {
+		level   string
+		message string
+		fields  map[string]interface{}
+	}{
+		{level: "DEBUG", message: "loading Lua script", fields: map[string]interface{}{"script": "loggerTest"}},
+		{level: "DEBUG", message: "Lua script loaded", fields: map[string]interface{}{"script": "loggerTest"}},
+		{level: "DEBUG", message: "hello from Lua", fields: map[string]interface{}{"function": "Test_LoggerPrint", "executionUuid": "logger-execution", "source": "print"}},
+		{level: "INFO", message: "placeholder dispatch decision", fields: map[string]interface{}{"function": "Test_LoggerPrint", "runtime": "lua"}},
+		{level: "WARN", message: "placeholder execution failed", fields: map[string]interface{}{"function": "Fenix_TodayShiftDay", "executionUuid": "logger-execution", "errorKind": "handler"}},
+		{level: "ERROR", message: "Lua script failed to load", fields: map[string]interface{}{"script": "loggerBroken", "phase": "compile"}},
+	}
+	for _, expectedLogRecord := range expectedLogRecords {
+		logRecord := findLogRecord(logRecords, expectedLogRecord.message, expectedLogRecord.fields)
+		t.Logf("  %s %q: %v", expectedLogRecord.level, expectedLogRecord.message, logRecord)
+		if logRecord == nil || logRecord["level"] != expectedLogRecord.level {
+			t.Fatalf("expected %s record %q with fields %v", expectedLogRecord.level, expectedLogRecord.message, expectedLogRecord.fields)
+		}
+	}
+}
+
+func TestSetLogger_ShouldBeSilentByDefault(t *testing.T) {
+	SetLogger(nil)
+	if scriptEngineLogger().Enabled(context.Background(), slog.LevelError) == true {
+		t.Fatalf("expected the default logger to discard everything")
+	}
+}
@@ -1,7 +1,6 @@
 package testDataEngine
 
 import (
-	"fmt"
 	"regexp"
 )
 
@@ -15,10 +14,10 @@ func BuildPopUpTableDataFromTestDataPointName(
 
 	matches := re.FindStringSubmatch(tempTestDataPointRowName)
 	if len(matches) > 2 {
-		fmt.Println("First part:", matches[1])
-		fmt.Println("Second part:", matches[2])
+		testDataEngineLogger().Debug("TestData point name split",
+			"testDataPointName", tempTestDataPointRowName, "firstPart", matches[1], "secondPart", matches[2])
 	} else {
-		fmt.Println("No matching parts found")
+		testDataEngineLogger().Debug("no matching parts found in TestData point name", "testDataPointName", tempTestDataPointRowName)
 	}
 
 	var tempTestDataModelMap map[TestDataDomainUuidType]*TestDataDomainModelStruct
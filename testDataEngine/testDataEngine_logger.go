@@ -0,0 +1,21 @@
+package testDataEngine
+
+import (
+	"log/slog"
+
+	"github.com/jlambert68/FenixScriptEngine/internal/engineLogger"
+)
+
+// testDataLog holds the logger for TestData diagnostics.
+var testDataLog engineLogger.Logger
+
+// SetLogger sets the logger that traces how TestData point names are split into their parts.
+// Without it, or after SetLogger(nil), TestData handling is not traced.
+func SetLogger(logger *slog.Logger) {
+	testDataLog.Set(logger)
+}
+
+// testDataEngineLogger returns the TestData logger.
+func testDataEngineLogger() *slog.Logger {
+	return testDataLog.Get()
+}